@@ -0,0 +1,102 @@
+// import: นำเข้า judgments จากไฟล์ CSV / NDJSON / XLSX (กติกาเดียวกับ POST /api/judgments/import)
+//
+//	go run ./cmd/import -file notes.xlsx -user admin@example.com -mapping '{"title":"ชื่อเรื่อง"}' -dry-run
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"judgment-notes/cmd/internal/db"
+	"judgment-notes/cmd/internal/httpapi"
+	"judgment-notes/cmd/internal/importer"
+	"judgment-notes/cmd/internal/thai"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	file := flag.String("file", "", "ไฟล์ที่จะนำเข้า (.csv, .ndjson, .jsonl, .xlsx)")
+	format := flag.String("format", "", "csv | ndjson | xlsx (ไม่ระบุ = เดาจากนามสกุลไฟล์)")
+	mapping := flag.String("mapping", "", `JSON field → column เช่น {"title":"ชื่อเรื่อง"}`)
+	dryRun := flag.Bool("dry-run", false, "ตรวจสอบอย่างเดียว ไม่บันทึก")
+	mode := flag.String("mode", "atomic", "atomic (tx เดียว) | chunked")
+	chunkSize := flag.Int("chunk-size", 500, "จำนวนแถวต่อ tx เมื่อ -mode chunked")
+	user := flag.String("user", "", "อีเมลของผู้นำเข้า (created_by)")
+	calendar := flag.String("calendar", "ce", "ระบบปีของ judgment_date ที่ไม่ได้เขียน พ.ศ./ค.ศ. กำกับ: ce | be")
+	flag.Parse()
+
+	if *file == "" || *user == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("Error loading .env file (using system env instead)")
+	}
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	opts := httpapi.ImportOptions{DryRun: *dryRun, ChunkSize: *chunkSize}
+	switch *mode {
+	case "atomic":
+	case "chunked":
+		opts.Chunked = true
+	default:
+		log.Fatal("-mode must be atomic or chunked")
+	}
+	cal, ok := thai.ParseCalendar(*calendar)
+	if !ok {
+		log.Fatal("-calendar must be ce or be")
+	}
+	opts.Calendar = cal
+	if *mapping != "" {
+		if err := json.Unmarshal([]byte(*mapping), &opts.Mapping); err != nil {
+			log.Fatal("invalid -mapping: ", err)
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	fmtName, err := importer.DetectFormat(*file, *format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rows, err := importer.Read(f, fmtName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	pool, err := db.New(dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pool.Close()
+
+	if opts.UserID, err = httpapi.ResolveUserID(ctx, pool, *user); err != nil {
+		log.Fatal(err)
+	}
+
+	report, err := httpapi.ImportJudgments(ctx, pool, rows, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
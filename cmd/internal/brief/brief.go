@@ -0,0 +1,190 @@
+// Package brief สร้าง PDF บันทึกย่อคำพิพากษา (case brief) ด้วย Go ล้วน ไม่พึ่งบริการภายนอก
+package brief
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+type Field struct {
+	Label string
+	Text  string
+}
+
+// Brief คือเนื้อหาของหนึ่งเรื่อง (เริ่มหน้าใหม่เสมอ)
+type Brief struct {
+	Title    string
+	Ref      string  // แสดงที่หัวกระดาษด้านขวา เช่น doc_no
+	Meta     []Field // ตารางรายละเอียดใต้ชื่อเรื่อง
+	Sections []Field // หัวข้อเนื้อหา
+}
+
+type Options struct {
+	Heading string    // หัวกระดาษด้านซ้าย
+	Printed time.Time // วันที่พิมพ์ที่ท้ายกระดาษ
+}
+
+// หน่วยเป็นมิลลิเมตร (A4)
+const (
+	marginX    = 20.0
+	marginTop  = 25.0
+	marginBot  = 20.0
+	labelWidth = 38.0
+	lineHeight = 7.0
+	fontFamily = "sarabun"
+)
+
+// Render เขียน PDF ของทุกเรื่องต่อกันเป็นไฟล์เดียว
+func Render(w io.Writer, opts Options, briefs ...Brief) error {
+	fs, err := loadFonts()
+	if err != nil {
+		return err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", fs.regular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", fs.bold)
+	pdf.SetMargins(marginX, marginTop, marginX)
+	pdf.SetAutoPageBreak(true, marginBot)
+	pdf.SetCreator("judgment-notes", true)
+	pdf.SetCreationDate(opts.Printed)
+	pdf.AliasNbPages("{nb}")
+	if len(briefs) == 1 {
+		pdf.SetTitle(briefs[0].Title, true)
+	}
+
+	r := &renderer{pdf: pdf}
+	pageW, pageH := pdf.GetPageSize()
+	r.width = pageW - 2*marginX
+
+	var current *Brief
+	pdf.SetHeaderFuncMode(func() {
+		pdf.SetFont(fontFamily, "", 12)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.SetXY(marginX, 10)
+		pdf.CellFormat(r.width/2, 6, opts.Heading, "", 0, "L", false, 0, "")
+		if current != nil {
+			pdf.CellFormat(r.width/2, 6, current.Ref, "", 0, "R", false, 0, "")
+		}
+		pdf.SetDrawColor(160, 160, 160)
+		pdf.Line(marginX, 17, pageW-marginX, 17)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetXY(marginX, marginTop)
+	}, false)
+	pdf.SetFooterFunc(func() {
+		pdf.SetFont(fontFamily, "", 12)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.SetDrawColor(160, 160, 160)
+		pdf.Line(marginX, pageH-15, pageW-marginX, pageH-15)
+		pdf.SetXY(marginX, pageH-14)
+		pdf.CellFormat(r.width/2, 6, "พิมพ์เมื่อ "+thai.FormatDateBE(opts.Printed), "", 0, "L", false, 0, "")
+		pdf.CellFormat(r.width/2, 6, "หน้า "+strconv.Itoa(pdf.PageNo())+"/{nb}", "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	for i := range briefs {
+		current = &briefs[i]
+		pdf.AddPage()
+		r.brief(briefs[i])
+	}
+	if len(briefs) == 0 {
+		pdf.AddPage()
+	}
+	return pdf.Output(w)
+}
+
+type renderer struct {
+	pdf   *gofpdf.Fpdf
+	width float64
+}
+
+func (r *renderer) brief(b Brief) {
+	pdf := r.pdf
+
+	pdf.SetFont(fontFamily, "B", 22)
+	r.lines(marginX, r.width, 9, r.wrap(b.Title, r.width))
+	pdf.Ln(3)
+
+	for _, f := range b.Meta {
+		pdf.SetFont(fontFamily, "B", 16)
+		pdf.SetX(marginX)
+		pdf.CellFormat(labelWidth, lineHeight, f.Label, "", 0, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 16)
+		r.lines(marginX+labelWidth, r.width-labelWidth, lineHeight, r.wrap(f.Text, r.width-labelWidth))
+	}
+
+	for _, s := range b.Sections {
+		pdf.Ln(4)
+		pdf.SetFont(fontFamily, "B", 18)
+		r.lines(marginX, r.width, 8, []string{s.Label})
+		pdf.SetFont(fontFamily, "", 16)
+		r.lines(marginX, r.width, lineHeight, r.wrap(s.Text, r.width))
+	}
+}
+
+// lines เขียนทีละบรรทัดที่ตำแหน่ง x (ขึ้นหน้าใหม่อัตโนมัติเมื่อถึงท้ายกระดาษ)
+func (r *renderer) lines(x, width, h float64, lines []string) {
+	for _, line := range lines {
+		r.pdf.SetX(x)
+		r.pdf.CellFormat(width, h, line, "", 1, "L", false, 0, "")
+	}
+}
+
+// wrap ตัดบรรทัดตามความกว้างด้วยฟอนต์ปัจจุบัน ภาษาไทยตัดที่ขอบคำ (thai.LineBreaks)
+func (r *renderer) wrap(text string, width float64) []string {
+	out := []string{}
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, piece := range thai.LineBreaks(para) {
+			if r.fits(line+piece, width) {
+				line += piece
+				continue
+			}
+			if line != "" {
+				out = append(out, strings.TrimRight(line, " "))
+			}
+			line = strings.TrimLeft(piece, " ")
+			// คำเดียวยาวเกินบรรทัด: ตัดกลางคำ
+			for !r.fits(line, width) {
+				head, tail := r.split(line, width)
+				out = append(out, head)
+				line = tail
+			}
+		}
+		out = append(out, strings.TrimRight(line, " "))
+	}
+	return out
+}
+
+func (r *renderer) fits(s string, width float64) bool {
+	return r.pdf.GetStringWidth(strings.TrimRight(s, " ")) <= width
+}
+
+// split ตัดส่วนหน้าที่ยาวที่สุดที่พอดีบรรทัด โดยไม่แยกสระบน/ล่างและวรรณยุกต์ออกจากพยัญชนะ
+func (r *renderer) split(s string, width float64) (string, string) {
+	rs := []rune(s)
+	cut := 0
+	for i := 1; i <= len(rs); i++ {
+		if i < len(rs) && unicode.Is(unicode.Mn, rs[i]) {
+			continue
+		}
+		if !r.fits(string(rs[:i]), width) {
+			break
+		}
+		cut = i
+	}
+	if cut == 0 {
+		cut = 1
+		for cut < len(rs) && unicode.Is(unicode.Mn, rs[cut]) {
+			cut++
+		}
+	}
+	return string(rs[:cut]), string(rs[cut:])
+}
@@ -0,0 +1,57 @@
+package brief
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderOnePage(t *testing.T) {
+	// repo ไม่มีไฟล์ฟอนต์: ทดสอบเมื่อชี้ฟอนต์ไทยด้วย env แบบเดียวกับที่ Dockerfile ตั้ง
+	if os.Getenv("PDF_FONT_REGULAR") == "" || os.Getenv("PDF_FONT_BOLD") == "" {
+		t.Skip("PDF_FONT_REGULAR / PDF_FONT_BOLD not set")
+	}
+	if err := Available(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err := Render(&buf, Options{
+		Heading: "บันทึกย่อคำพิพากษา",
+		Printed: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+	}, Brief{
+		Title: "คำพิพากษาศาลฎีกาที่ ๑๒๓๔/๒๕๖๗",
+		Ref:   "JN-2567-0001",
+		Meta:  []Field{{Label: "ศาล", Text: "ศาลฎีกา"}, {Label: "วันที่", Text: "15 มีนาคม 2567"}},
+		Sections: []Field{
+			{Label: "ข้อเท็จจริง", Text: strings.Repeat("จำเลยทำสัญญากู้ยืมเงินกับโจทก์ ", 10)},
+			{Label: "ประเด็น", Text: "สัญญากู้ยืมเงินมีผลบังคับหรือไม่"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-") {
+		t.Fatalf("not a PDF: %q", out[:min(len(out), 16)])
+	}
+	if !strings.Contains(out, "/Count 1") {
+		t.Fatal("expected exactly one page")
+	}
+}
+
+func TestReadFontMissing(t *testing.T) {
+	t.Setenv("PDF_FONT_TEST", "")
+	_, err := readFont("PDF_FONT_TEST", "fonts/missing.ttf")
+	if err == nil || !strings.Contains(err.Error(), "PDF_FONT_TEST") {
+		t.Fatalf("err = %v, want a hint to set PDF_FONT_TEST", err)
+	}
+
+	t.Setenv("PDF_FONT_TEST", "testdata/missing.ttf")
+	if _, err := readFont("PDF_FONT_TEST", "fonts/missing.ttf"); err == nil {
+		t.Fatal("expected an error for a missing PDF_FONT_TEST file")
+	}
+}
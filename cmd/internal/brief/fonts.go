@@ -0,0 +1,53 @@
+package brief
+
+import (
+	"embed"
+	"errors"
+	"os"
+	"sync"
+)
+
+// ใน repo มีแค่ fonts/README.md (ไม่ได้แจกไฟล์ฟอนต์) จะมีฟอนต์ไทยฝังใน binary ก็ต่อเมื่อวาง .ttf ไว้ก่อน build
+// ไม่งั้นต้องตั้ง PDF_FONT_REGULAR / PDF_FONT_BOLD (Docker image ตั้งไว้แล้ว)
+//
+//go:embed fonts
+var embeddedFonts embed.FS
+
+type fontSet struct {
+	regular, bold []byte
+}
+
+var (
+	fontsOnce sync.Once
+	fonts     fontSet
+	fontsErr  error
+)
+
+// loadFonts อ่านฟอนต์จาก PDF_FONT_REGULAR / PDF_FONT_BOLD ถ้ากำหนดไว้ ไม่งั้นลองหาใน fonts/ ที่ฝังไว้ตอน build
+func loadFonts() (fontSet, error) {
+	fontsOnce.Do(func() {
+		fonts.regular, fontsErr = readFont("PDF_FONT_REGULAR", "fonts/THSarabunNew.ttf")
+		if fontsErr != nil {
+			return
+		}
+		fonts.bold, fontsErr = readFont("PDF_FONT_BOLD", "fonts/THSarabunNew-Bold.ttf")
+	})
+	return fonts, fontsErr
+}
+
+func readFont(env, embedded string) ([]byte, error) {
+	if p := os.Getenv(env); p != "" {
+		return os.ReadFile(p)
+	}
+	b, err := embeddedFonts.ReadFile(embedded)
+	if err != nil {
+		return nil, errors.New("pdf font not found: add " + embedded + " or set " + env)
+	}
+	return b, nil
+}
+
+// Available บอกว่ามีฟอนต์พร้อมสร้าง PDF หรือไม่ (ใช้ตรวจก่อนเริ่มส่ง response)
+func Available() error {
+	_, err := loadFonts()
+	return err
+}
@@ -0,0 +1,101 @@
+// Package docno สร้างเลข doc_no ตาม template ของรูปแบบเลขเอกสาร (doc_no_schemes)
+//
+// token ที่ใช้ได้: {year} ปี 4 หลัก, {yy} ปี 2 หลัก, {court} รหัสศาล, {category} หมวด, {seq} เลขลำดับ
+package docno
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// รอบการเริ่มนับเลขลำดับใหม่
+const (
+	ResetYearly = "yearly"
+	ResetNever  = "never"
+)
+
+// ค่าแทน {court} / {category} เมื่อ judgment ไม่มีค่านั้น
+const Missing = "X"
+
+var tokenRe = regexp.MustCompile(`\{([a-z]+)\}`)
+
+var tokens = []string{"year", "yy", "court", "category", "seq"}
+
+// Values คือค่าที่ใช้แทน token
+type Values struct {
+	Year     int
+	Court    string
+	Category string
+	Seq      int
+}
+
+// Validate ตรวจ template: token ต้องรู้จัก, ต้องมี {seq} และถ้านับใหม่ทุกปีต้องมีปีในเลขด้วย (ไม่งั้นเลขซ้ำข้ามปี)
+func Validate(template, reset string, padding int) error {
+	if strings.TrimSpace(template) == "" {
+		return errors.New("template is required")
+	}
+	if padding < 1 || padding > 10 {
+		return errors.New("padding must be between 1 and 10")
+	}
+	seen := map[string]bool{}
+	for _, m := range tokenRe.FindAllStringSubmatch(template, -1) {
+		known := false
+		for _, t := range tokens {
+			known = known || t == m[1]
+		}
+		if !known {
+			return errors.New("unknown token {" + m[1] + "}")
+		}
+		seen[m[1]] = true
+	}
+	if !seen["seq"] {
+		return errors.New("template must contain {seq}")
+	}
+	switch reset {
+	case ResetYearly:
+		if !seen["year"] && !seen["yy"] {
+			return errors.New("a yearly counter needs {year} or {yy} in the template")
+		}
+	case ResetNever:
+	default:
+		return errors.New("reset must be yearly or never")
+	}
+	return nil
+}
+
+// Format แทน token ด้วยค่าจริง เช่น "JG-{year}-{seq}" → "JG-2567-0001"
+func Format(template string, padding int, v Values) string {
+	return tokenRe.ReplaceAllStringFunc(template, func(tok string) string {
+		switch tok {
+		case "{year}":
+			return strconv.Itoa(v.Year)
+		case "{yy}":
+			return fmt.Sprintf("%02d", v.Year%100)
+		case "{court}":
+			return orMissing(v.Court)
+		case "{category}":
+			return orMissing(v.Category)
+		case "{seq}":
+			return fmt.Sprintf("%0*d", padding, v.Seq)
+		}
+		return tok
+	})
+}
+
+// Period คือ key ของตัวนับ: ปี ถ้านับใหม่ทุกปี, 0 ถ้าไม่เริ่มใหม่
+func Period(reset string, year int) int {
+	if reset == ResetYearly {
+		return year
+	}
+	return 0
+}
+
+func orMissing(s string) string {
+	if s = strings.TrimSpace(s); s == "" {
+		return Missing
+	}
+	return s
+}
@@ -0,0 +1,61 @@
+package docno
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		template, reset string
+		padding         int
+		ok              bool
+	}{
+		{"JG-{year}-{seq}", ResetYearly, 4, true},
+		{"{court}/{yy}/{seq}", ResetYearly, 1, true},
+		{"{category}-{seq}", ResetNever, 10, true},
+		{"", ResetNever, 4, false},
+		{"   ", ResetNever, 4, false},
+		{"JG-{year}", ResetYearly, 4, false},
+		{"JG-{year}-{seq}", ResetYearly, 0, false},
+		{"JG-{year}-{seq}", ResetYearly, 11, false},
+		{"JG-{month}-{seq}", ResetNever, 4, false},
+		// นับใหม่ทุกปีแต่ไม่มีปี → เลขซ้ำข้ามปี
+		{"JG-{seq}", ResetYearly, 4, false},
+		{"JG-{year}-{seq}", "monthly", 4, false},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.template, tt.reset, tt.padding)
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate(%q, %q, %d) = %v, want ok=%v", tt.template, tt.reset, tt.padding, err, tt.ok)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		template string
+		padding  int
+		v        Values
+		want     string
+	}{
+		{"JG-{year}-{seq}", 4, Values{Year: 2567, Seq: 1}, "JG-2567-0001"},
+		{"JG-{year}-{seq}", 1, Values{Year: 2567, Seq: 12345}, "JG-2567-12345"},
+		{"{yy}/{seq}", 3, Values{Year: 2567, Seq: 7}, "67/007"},
+		{"{yy}/{seq}", 3, Values{Year: 2009, Seq: 7}, "09/007"},
+		{"{court}-{category}-{seq}", 2, Values{Court: "SC", Category: "แพ่ง", Seq: 3}, "SC-แพ่ง-03"},
+		{"{court}-{category}-{seq}", 2, Values{Court: " ", Seq: 3}, Missing + "-" + Missing + "-03"},
+		{"{court}{court}-{seq}", 1, Values{Court: "A", Seq: 1}, "AA-1"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.template, tt.padding, tt.v); got != tt.want {
+			t.Errorf("Format(%q, %d, %+v) = %q, want %q", tt.template, tt.padding, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	if got := Period(ResetYearly, 2567); got != 2567 {
+		t.Errorf("Period(yearly) = %d, want 2567", got)
+	}
+	if got := Period(ResetNever, 2567); got != 0 {
+		t.Errorf("Period(never) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,124 @@
+// Package extract ดึงข้อความจากไฟล์แนบด้วย Go ล้วน: PDF ที่มี text layer, DOCX และข้อความธรรมดา
+// (ไม่ทำ OCR ไฟล์สแกนจะได้ข้อความว่าง)
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+
+	"judgment-notes/cmd/internal/office"
+)
+
+var ErrUnsupported = errors.New("extract: unsupported file type")
+
+const DocxType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// Supported บอกว่าชนิดไฟล์นี้ดึงข้อความได้หรือไม่
+func Supported(contentType string) bool {
+	switch contentType {
+	case "application/pdf", DocxType, "text/plain":
+		return true
+	}
+	return false
+}
+
+// Text ดึงข้อความตามชนิดไฟล์ (content type ที่ตรวจจากเนื้อไฟล์แล้ว)
+// ผลลัพธ์เป็น UTF-8 ที่ถูกต้องและไม่มีตัวอักษร NUL (Postgres เก็บไม่ได้)
+func Text(r io.ReaderAt, size int64, contentType string) (string, error) {
+	var text string
+	var err error
+	switch contentType {
+	case "application/pdf":
+		text, err = pdfText(r, size)
+	case DocxType:
+		text, err = office.DocxText(r, size)
+	case "text/plain":
+		text, err = plainText(io.NewSectionReader(r, 0, size))
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+	return clean(text), nil
+}
+
+func pdfText(r io.ReaderAt, size int64) (text string, err error) {
+	// library panic กับไฟล์เสียบางแบบ
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("pdf: %v", p)
+		}
+	}()
+
+	pr, err := pdf.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fonts := map[string]*pdf.Font{}
+	for i := 1; i <= pr.NumPage(); i++ {
+		page := pr.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				f := page.Font(name)
+				fonts[name] = &f
+			}
+		}
+		s, err := page.GetPlainText(fonts)
+		if err != nil {
+			return "", fmt.Errorf("pdf page %d: %w", i, err)
+		}
+		b.WriteString(s)
+		b.WriteString("\n\f\n") // คั่นหน้า
+	}
+	return b.String(), nil
+}
+
+// plainText อ่านไฟล์ข้อความ ถ้าไม่ใช่ UTF-8 ถือว่าเป็น TIS-620 / Windows-874 (ไฟล์ภาษาไทยรุ่นเก่า)
+func plainText(r io.Reader) (string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	b = []byte(strings.TrimPrefix(string(b), "\ufeff"))
+	if utf8.Valid(b) {
+		return string(b), nil
+	}
+	return decodeTIS620(b), nil
+}
+
+func decodeTIS620(b []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(b) * 3)
+	for _, c := range b {
+		switch {
+		case c < 0x80:
+			sb.WriteByte(c)
+		case c >= 0xA1 && c <= 0xFB:
+			sb.WriteRune(rune(c-0xA1) + 0x0E01)
+		default:
+			sb.WriteRune(utf8.RuneError)
+		}
+	}
+	return sb.String()
+}
+
+// clean ตัด byte ที่ไม่ใช่ UTF-8 และตัวอักษรควบคุม (รวม NUL) เหลือแค่ขึ้นบรรทัด/tab/ขึ้นหน้า
+func clean(s string) string {
+	s = strings.ToValidUTF8(s, "")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' && r != '\t' && r != '\f' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,309 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+
+	"judgment-notes/cmd/internal/mail"
+)
+
+// ลืมรหัสผ่าน / ยืนยันอีเมล: token ส่งทางอีเมล ใช้ได้ครั้งเดียว มีอายุ เก็บเฉพาะ sha256 ใน user_tokens
+var (
+	// REQUIRE_EMAIL_VERIFICATION=true: login ไม่ได้จนกว่าจะยืนยันอีเมล
+	requireEmailVerification = strings.EqualFold(getEnv("REQUIRE_EMAIL_VERIFICATION", "false"), "true")
+	appBaseURL               = strings.TrimRight(getEnv("APP_BASE_URL", "http://localhost:3000"), "/")
+	passwordResetTTL         = envDuration("PASSWORD_RESET_TTL", time.Hour)
+	emailVerifyTTL           = envDuration("EMAIL_VERIFY_TTL", 48*time.Hour)
+	defaultMailLang          = mail.Lang(getEnv("MAIL_LANG", mail.Thai), mail.Thai)
+)
+
+const (
+	tokenPasswordReset = "password_reset"
+	tokenEmailVerify   = "email_verify"
+
+	// ขอส่งอีเมลซ้ำได้ไม่เกิน 1 ครั้งต่อนาที (ต่อ user ต่อประเภท)
+	accountMailInterval = time.Minute
+)
+
+var errAccountToken = errors.New("invalid or expired token")
+
+type accountEmailPayload struct {
+	Email string `json:"email"`
+	Lang  string `json:"lang"` // th | en (ไม่ส่งใช้ Accept-Language)
+}
+
+type resetPasswordPayload struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func registerAccountRoutes(api *gin.RouterGroup, pool *pgxpool.Pool, mailer mail.Mailer) {
+	api.POST("/auth/forgot", func(c *gin.Context) { forgotPassword(c, pool, mailer) })
+	api.POST("/auth/reset", func(c *gin.Context) { resetPassword(c, pool) })
+	api.POST("/auth/verify", func(c *gin.Context) { verifyEmail(c, pool) })
+	api.POST("/auth/verify/resend", func(c *gin.Context) { resendVerification(c, pool, mailer) })
+}
+
+// forgotPassword ตอบ 200 เสมอ ไม่บอกว่ามีอีเมลนี้ในระบบหรือไม่
+func forgotPassword(c *gin.Context, pool *pgxpool.Pool, mailer mail.Mailer) {
+	var in accountEmailPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(in.Email))
+	if email == "" {
+		c.JSON(400, gin.H{"error": "email is required"})
+		return
+	}
+
+	var userID string
+	err := pool.QueryRow(c, `SELECT id FROM users WHERE email = $1`, email).Scan(&userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err == nil {
+		if err := sendAccountEmail(c, pool, mailer, userID, tokenPasswordReset, mailLang(c, in.Lang), true); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(200, gin.H{"ok": true})
+}
+
+// resetPassword ตั้งรหัสผ่านใหม่ด้วย token จากอีเมล แล้วยกเลิก session เดิมทั้งหมด
+func resetPassword(c *gin.Context, pool *pgxpool.Pool) {
+	var in resetPasswordPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if strings.TrimSpace(in.Token) == "" {
+		c.JSON(400, gin.H{"error": "token is required"})
+		return
+	}
+	if len(in.Password) < 6 {
+		c.JSON(400, gin.H{"error": "password must be at least 6 characters"})
+		return
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to hash password"})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	userID, err := consumeUserToken(c, tx, in.Token, tokenPasswordReset)
+	if errors.Is(err, errAccountToken) {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	// ได้รับลิงก์ทางอีเมลแปลว่าอีเมลใช้ได้จริง
+	if _, err := tx.Exec(c, `
+UPDATE users SET password_hash = $2, email_verified_at = COALESCE(email_verified_at, now())
+WHERE id = $1`, userID, string(hashed)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := revokeUserSessions(c, tx, userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	// key ที่ผู้บุกรุกอาจสร้างไว้ต้องใช้ไม่ได้ด้วย
+	if _, err := revokeUserAPIKeys(c, tx, userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(204)
+}
+
+func verifyEmail(c *gin.Context, pool *pgxpool.Pool) {
+	var in struct {
+		Token string `json:"token"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil || strings.TrimSpace(in.Token) == "" {
+		c.JSON(400, gin.H{"error": "token is required"})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	userID, err := consumeUserToken(c, tx, in.Token, tokenEmailVerify)
+	if errors.Is(err, errAccountToken) {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := tx.Exec(c, `UPDATE users SET email_verified_at = COALESCE(email_verified_at, now()) WHERE id = $1`, userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"email_verified": true})
+}
+
+// resendVerification ส่งลิงก์ยืนยันใหม่ (ใช้ตอน login ไม่ได้เพราะยังไม่ยืนยัน) ตอบ 200 เสมอ
+func resendVerification(c *gin.Context, pool *pgxpool.Pool, mailer mail.Mailer) {
+	var in accountEmailPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(in.Email))
+	if email == "" {
+		c.JSON(400, gin.H{"error": "email is required"})
+		return
+	}
+
+	var userID string
+	err := pool.QueryRow(c, `SELECT id FROM users WHERE email = $1 AND email_verified_at IS NULL`, email).Scan(&userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err == nil {
+		if err := sendAccountEmail(c, pool, mailer, userID, tokenEmailVerify, mailLang(c, in.Lang), true); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(200, gin.H{"ok": true})
+}
+
+// sendAccountEmail ออก token ใหม่ (token เดิมที่ยังไม่ใช้ของประเภทเดียวกันใช้ไม่ได้อีก) แล้วส่งอีเมลแบบ background
+// throttle=true: ข้ามถ้าเพิ่งส่งไปไม่ถึง accountMailInterval (route public)
+func sendAccountEmail(ctx context.Context, q querier, mailer mail.Mailer, userID, purpose, lang string, throttle bool) error {
+	m, err := prepareAccountEmail(ctx, q, userID, purpose, lang, throttle)
+	if err != nil {
+		return err
+	}
+	m.send(mailer)
+	return nil
+}
+
+// accountEmail คืออีเมลที่ออก token แล้ว รอส่ง (ถ้าออก token ใน tx ให้ส่งหลัง commit)
+type accountEmail struct {
+	userID, purpose string
+	msg             mail.Message
+}
+
+// prepareAccountEmail ออก token และเตรียมอีเมล คืน nil ถ้าถูก throttle
+func prepareAccountEmail(ctx context.Context, q querier, userID, purpose, lang string, throttle bool) (*accountEmail, error) {
+	var email, name string
+	var recent bool
+	err := q.QueryRow(ctx, `
+SELECT u.email, u.name,
+       EXISTS (SELECT 1 FROM user_tokens t
+               WHERE t.user_id = u.id AND t.purpose = $2 AND t.used_at IS NULL AND t.created_at > $3)
+FROM users u WHERE u.id = $1`, userID, purpose, time.Now().Add(-accountMailInterval)).Scan(&email, &name, &recent)
+	if err != nil {
+		return nil, err
+	}
+	if throttle && recent {
+		return nil, nil
+	}
+
+	ttl, path := emailVerifyTTL, "/verify-email"
+	if purpose == tokenPasswordReset {
+		ttl, path = passwordResetTTL, "/reset-password"
+	}
+	token, err := issueUserToken(ctx, q, userID, purpose, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := mail.Render(purpose, lang, email, mail.Data{
+		Name:    name,
+		Link:    appBaseURL + path + "?token=" + url.QueryEscape(token),
+		Minutes: int(ttl / time.Minute),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &accountEmail{userID: userID, purpose: purpose, msg: msg}, nil
+}
+
+// send ส่งแบบ background ส่งไม่สำเร็จแค่ log ไว้ (ผู้ใช้ขอส่งใหม่ได้) m หรือ mailer เป็น nil ก็ไม่ทำอะไร
+func (m *accountEmail) send(mailer mail.Mailer) {
+	if m == nil || mailer == nil {
+		return
+	}
+	// ไม่รอ SMTP: ตอบเร็วและเวลาตอบไม่บอกว่ามีบัญชีนี้หรือไม่
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := mailer.Send(ctx, m.msg); err != nil {
+			log.Printf("mail: send %s to user %s: %v", m.purpose, m.userID, err)
+		}
+	}()
+}
+
+func issueUserToken(ctx context.Context, q querier, userID, purpose string, ttl time.Duration) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	if _, err := q.Exec(ctx, `DELETE FROM user_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`, userID, purpose); err != nil {
+		return "", err
+	}
+	_, err = q.Exec(ctx, `
+INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)`, userID, purpose, hashToken(token), time.Now().Add(ttl))
+	return token, err
+}
+
+// consumeUserToken ใช้ token (ครั้งเดียว) คืน user_id
+func consumeUserToken(ctx context.Context, q querier, token, purpose string) (string, error) {
+	var userID string
+	err := q.QueryRow(ctx, `
+UPDATE user_tokens SET used_at = now()
+WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+RETURNING user_id`, hashToken(strings.TrimSpace(token)), purpose).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", errAccountToken
+	}
+	return userID, err
+}
+
+// mailLang: ภาษาที่ส่งมาใน payload > Accept-Language > MAIL_LANG
+func mailLang(c *gin.Context, lang string) string {
+	if lang == "" {
+		lang = c.GetHeader("Accept-Language")
+	}
+	return mail.Lang(lang, defaultMailLang)
+}
@@ -0,0 +1,37 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"judgment-notes/cmd/internal/mail"
+)
+
+type failingMailer struct{ called chan mail.Message }
+
+func (m failingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.called <- msg
+	return errors.New("smtp down")
+}
+
+// ส่งอีเมลไม่สำเร็จต้องไม่กระทบผู้เรียก (สมัครสมาชิก commit ไปแล้ว)
+func TestAccountEmailSendIsNonFatal(t *testing.T) {
+	var nilEmail *accountEmail
+	nilEmail.send(failingMailer{}) // ถูก throttle: ไม่มีอะไรให้ส่ง
+
+	m := &accountEmail{userID: "u1", purpose: tokenEmailVerify, msg: mail.Message{To: "a@example.com"}}
+	m.send(nil) // ไม่ได้ตั้ง mailer
+
+	mailer := failingMailer{called: make(chan mail.Message, 1)}
+	m.send(mailer)
+	select {
+	case got := <-mailer.called:
+		if got.To != "a@example.com" {
+			t.Errorf("sent to %q", got.To)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("mailer was not called")
+	}
+}
@@ -0,0 +1,239 @@
+package httpapi
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// API key ให้ script/ระบบอื่นเรียก API แทนการถือรหัสผ่าน (Authorization: Bearer jnk_...)
+// key ทำได้ไม่เกินสิทธิ์ของเจ้าของ และถูกจำกัดด้วย scope (ดู RequireScope)
+const apiKeyPrefix = "jnk_"
+
+// scope ของ API key (":write" ครอบคลุม ":read" ของ resource เดียวกัน)
+const (
+	scopeJudgmentsRead  = "judgments:read"
+	scopeJudgmentsWrite = "judgments:write"
+	scopeUsersAdmin     = "users:admin"
+)
+
+var validAPIKeyScopes = []string{scopeJudgmentsRead, scopeJudgmentsWrite, scopeUsersAdmin}
+
+// อายุ key ค่าเริ่มต้น (วัน) เมื่อไม่ส่ง expires_in_days
+var apiKeyDefaultDays = envInt("API_KEY_DEFAULT_DAYS", 90)
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // ต้นของ key ไว้จำว่าเป็น key ไหน
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP *string    `json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type createAPIKeyPayload struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays *int     `json:"expires_in_days"` // ไม่ส่ง = API_KEY_DEFAULT_DAYS, 0 = ไม่หมดอายุ
+}
+
+func registerAPIKeyRoutes(api *gin.RouterGroup, pool *pgxpool.Pool) {
+	keys := api.Group("/auth/api-keys")
+	keys.Use(AuthMiddleware(pool), requireSession)
+	keys.GET("", func(c *gin.Context) { listAPIKeys(c, pool, c.GetString("userID")) })
+	keys.POST("", func(c *gin.Context) { createAPIKey(c, pool) })
+	keys.DELETE("/:id", func(c *gin.Context) { revokeAPIKey(c, pool, c.GetString("userID"), c.Param("id")) })
+}
+
+func registerUserAPIKeyAdminRoutes(admin *gin.RouterGroup, pool *pgxpool.Pool) {
+	admin.GET("/users/:id/api-keys", func(c *gin.Context) { listAPIKeys(c, pool, c.Param("id")) })
+	admin.DELETE("/users/:id/api-keys/:keyId", func(c *gin.Context) {
+		revokeAPIKey(c, pool, c.Param("id"), c.Param("keyId"))
+	})
+}
+
+// requireSession: route จัดการ API key ต้องเรียกด้วย login จริง (ใช้ key สร้าง key ใหม่ไม่ได้)
+func requireSession(c *gin.Context) {
+	if c.GetString("sessionID") == "" {
+		c.JSON(403, gin.H{"error": "this endpoint requires a login session"})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// authenticateAPIKey ตรวจ key แล้วใส่ข้อมูล user ลง context แบบเดียวกับ JWT (ไม่มี sessionID)
+// ไม่ผ่านจะตอบ error และ abort ให้เลย
+func authenticateAPIKey(c *gin.Context, pool *pgxpool.Pool, key string) bool {
+	var keyID, userID, role, email string
+	var scopes []string
+	var lastUsed *time.Time
+	var mfaPending bool
+	err := pool.QueryRow(c, `
+SELECT k.id, k.scopes, k.last_used_at, u.id, u.role, u.email,
+       u.totp_enabled_at IS NULL AND EXISTS (SELECT 1 FROM roles r WHERE r.name = u.role AND r.require_mfa)
+FROM api_keys k JOIN users u ON u.id = k.user_id
+WHERE k.token_hash = $1 AND k.revoked_at IS NULL AND (k.expires_at IS NULL OR k.expires_at > now())`,
+		hashToken(key)).Scan(&keyID, &scopes, &lastUsed, &userID, &role, &email, &mfaPending)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "invalid, expired or revoked api key"})
+		c.Abort()
+		return false
+	}
+	// role บังคับ 2FA: ต้องเปิด 2FA ก่อน key ถึงจะใช้ได้
+	if mfaPending {
+		c.JSON(403, gin.H{"error": "two-factor authentication is required for your role", "mfa_setup_required": true})
+		c.Abort()
+		return false
+	}
+	if lastUsed == nil || time.Since(*lastUsed) > sessionSeenInterval {
+		if _, err := pool.Exec(c, `UPDATE api_keys SET last_used_at = now(), last_used_ip = COALESCE($2, last_used_ip) WHERE id = $1`,
+			keyID, nullIfEmpty(c.ClientIP())); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			c.Abort()
+			return false
+		}
+	}
+
+	setAPIKeyContext(c, keyID, userID, email, role, scopes)
+	return true
+}
+
+// setAPIKeyContext ใส่ user ของ key ลง context: ไม่มี sessionID (route ที่ requireSession จึงใช้ key ไม่ได้)
+// และมี apiKeyScopes ให้ RequireScope ตรวจ
+func setAPIKeyContext(c *gin.Context, keyID, userID, email, role string, scopes []string) {
+	c.Set("userID", userID)
+	c.Set("userEmail", email)
+	c.Set("userRole", role)
+	c.Set("apiKeyID", keyID)
+	c.Set("apiKeyScopes", scopes)
+}
+
+func createAPIKey(c *gin.Context, pool *pgxpool.Pool) {
+	var in createAPIKeyPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		c.JSON(400, gin.H{"error": "name is required (max 100 characters)"})
+		return
+	}
+
+	scopes := []string{}
+	for _, s := range in.Scopes {
+		if !containsString(validAPIKeyScopes, s) {
+			c.JSON(400, gin.H{"error": "unknown scope: " + s, "valid_scopes": validAPIKeyScopes})
+			return
+		}
+		if !containsString(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	if len(scopes) == 0 {
+		c.JSON(400, gin.H{"error": "at least one scope is required", "valid_scopes": validAPIKeyScopes})
+		return
+	}
+	if containsString(scopes, scopeUsersAdmin) && !hasRole(c, "admin") {
+		c.JSON(403, gin.H{"error": "only admins can create keys with scope " + scopeUsersAdmin})
+		return
+	}
+
+	days := apiKeyDefaultDays
+	if in.ExpiresInDays != nil {
+		days = *in.ExpiresInDays
+	}
+	if days < 0 {
+		c.JSON(400, gin.H{"error": "expires_in_days must be >= 0"})
+		return
+	}
+	var expiresAt *time.Time
+	if days > 0 {
+		t := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+		expiresAt = &t
+	}
+
+	secret, err := newOpaqueToken()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to generate key"})
+		return
+	}
+	token := apiKeyPrefix + secret
+
+	var k APIKey
+	err = pool.QueryRow(c, `
+INSERT INTO api_keys (user_id, name, token_prefix, token_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, name, token_prefix, scopes, expires_at, last_used_at, last_used_ip, created_at`,
+		c.GetString("userID"), name, token[:len(apiKeyPrefix)+6], hashToken(token), scopes, expiresAt).Scan(
+		&k.ID, &k.Name, &k.Prefix, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.LastUsedIP, &k.CreatedAt)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	// key เต็มแสดงครั้งเดียว ระบบเก็บไว้แค่ hash
+	c.JSON(201, gin.H{"api_key": k, "token": token})
+}
+
+// listAPIKeys แสดง key ที่ยังไม่ถูกยกเลิก (รวมที่หมดอายุแล้ว) ล่าสุดก่อน
+func listAPIKeys(c *gin.Context, pool *pgxpool.Pool, userID string) {
+	rows, err := pool.Query(c, `
+SELECT id, name, token_prefix, scopes, expires_at, last_used_at, last_used_ip, created_at
+FROM api_keys
+WHERE user_id::text = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC`, userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	out := make([]APIKey, 0)
+	for rows.Next() {
+		var k APIKey
+		if err := rows.Scan(&k.ID, &k.Name, &k.Prefix, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.LastUsedIP, &k.CreatedAt); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		out = append(out, k)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, out)
+}
+
+// revokeUserAPIKeys ยกเลิก key ทั้งหมดของ user (ใช้คู่กับ revokeUserSessions ตอนกู้บัญชี/ออกจากระบบทุกที่)
+func revokeUserAPIKeys(ctx context.Context, q querier, userID string) (int64, error) {
+	ct, err := q.Exec(ctx, `
+UPDATE api_keys SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+func revokeAPIKey(c *gin.Context, pool *pgxpool.Pool, userID, keyID string) {
+	ct, err := pool.Exec(c, `
+UPDATE api_keys SET revoked_at = now()
+WHERE id::text = $1 AND user_id::text = $2 AND revoked_at IS NULL`, keyID, userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if ct.RowsAffected() == 0 {
+		c.JSON(404, gin.H{"error": "api key not found"})
+		return
+	}
+	c.Status(204)
+}
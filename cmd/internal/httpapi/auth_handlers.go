@@ -10,26 +10,33 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
+
+	"judgment-notes/cmd/internal/mail"
 )
 
 type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	AvatarURL *string   `json:"avatar_url"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	Name          string    `json:"name"`
+	Role          string    `json:"role"`
+	AvatarURL     *string   `json:"avatar_url"`
+	MFAEnabled    bool      `json:"mfa_enabled"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type loginPayload struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	DeviceLabel string `json:"device_label"` // optional เช่น "เครื่องห้องพิจารณา 3"
 }
 
 type registerPayload struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Name     string `json:"name"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	Name        string `json:"name"`
+	DeviceLabel string `json:"device_label"`
+	Lang        string `json:"lang"` // ภาษาอีเมลยืนยัน th | en
 }
 
 var jwtSecret = []byte(getEnv("JWT_SECRET", "your-secret-key-change-in-production"))
@@ -41,11 +48,14 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-func registerAuthRoutes(api *gin.RouterGroup, pool *pgxpool.Pool) {
+func registerAuthRoutes(api *gin.RouterGroup, pool *pgxpool.Pool, mailer mail.Mailer) {
 	api.POST("/auth/login", func(c *gin.Context) { login(c, pool) })
-	api.POST("/auth/register", func(c *gin.Context) { register(c, pool) })
-	api.GET("/auth/me", AuthMiddleware(), func(c *gin.Context) { getMe(c, pool) })
-	api.POST("/auth/logout", func(c *gin.Context) { logout(c) })
+	api.POST("/auth/register", func(c *gin.Context) { register(c, pool, mailer) })
+	api.GET("/auth/me", mfaSetupMiddleware(pool), func(c *gin.Context) { getMe(c, pool) })
+	registerSessionRoutes(api, pool)
+	registerMFARoutes(api, pool)
+	registerAccountRoutes(api, pool, mailer)
+	registerAPIKeyRoutes(api, pool)
 }
 
 func login(c *gin.Context, pool *pgxpool.Pool) {
@@ -59,10 +69,10 @@ func login(c *gin.Context, pool *pgxpool.Pool) {
 	var user User
 	var passwordHash string
 	err := pool.QueryRow(c, `
-		SELECT id, email, name, role, avatar_url, created_at, password_hash 
+		SELECT id, email, name, role, avatar_url, totp_enabled_at IS NOT NULL, email_verified_at IS NOT NULL, created_at, password_hash 
 		FROM users WHERE email = $1
 	`, strings.ToLower(strings.TrimSpace(in.Email))).Scan(
-		&user.ID, &user.Email, &user.Name, &user.Role, &user.AvatarURL, &user.CreatedAt, &passwordHash,
+		&user.ID, &user.Email, &user.Name, &user.Role, &user.AvatarURL, &user.MFAEnabled, &user.EmailVerified, &user.CreatedAt, &passwordHash,
 	)
 	if err != nil {
 		c.JSON(401, gin.H{"error": "invalid email or password"})
@@ -75,28 +85,23 @@ func login(c *gin.Context, pool *pgxpool.Pool) {
 		return
 	}
 
-	// Generate JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":   user.ID,
-		"email": user.Email,
-		"name":  user.Name,
-		"role":  user.Role,
-		"exp":   time.Now().Add(24 * 7 * time.Hour).Unix(), // 7 days
-	})
+	// REQUIRE_EMAIL_VERIFICATION: ต้องยืนยันอีเมลก่อน (ขอลิงก์ใหม่ที่ /auth/verify/resend)
+	if requireEmailVerification && !user.EmailVerified {
+		c.JSON(403, gin.H{"error": "email address is not verified", "email_verification_required": true})
+		return
+	}
 
-	tokenString, err := token.SignedString(jwtSecret)
-	if err != nil {
-		c.JSON(500, gin.H{"error": "failed to generate token"})
+	// เปิด 2FA ไว้: ตอบ challenge token ให้ส่งรหัสต่อที่ /auth/login/mfa
+	if user.MFAEnabled {
+		startMFAChallenge(c, pool, user, in.DeviceLabel)
 		return
 	}
 
-	c.JSON(200, gin.H{
-		"token": tokenString,
-		"user":  user,
-	})
+	// access token อายุสั้น + refresh token ผูกกับ session
+	startSession(c, pool, user, in.DeviceLabel, 200)
 }
 
-func register(c *gin.Context, pool *pgxpool.Pool) {
+func register(c *gin.Context, pool *pgxpool.Pool, mailer mail.Mailer) {
 	var in registerPayload
 	if err := c.ShouldBindJSON(&in); err != nil {
 		c.JSON(400, gin.H{"error": "invalid payload"})
@@ -123,14 +128,22 @@ func register(c *gin.Context, pool *pgxpool.Pool) {
 		return
 	}
 
+	// user กับ token ยืนยันอีเมลต้องเกิดพร้อมกัน ไม่งั้นอาจได้บัญชีที่ยืนยันไม่ได้
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
 	// Insert user
 	var user User
-	err = pool.QueryRow(c, `
+	err = tx.QueryRow(c, `
     INSERT INTO users (email, password_hash, name, role)
     VALUES ($1, $2, $3, 'user')
-    RETURNING id, email, name, role, avatar_url, created_at
+    RETURNING id, email, name, role, avatar_url, email_verified_at IS NOT NULL, created_at
 `, email, string(hashedPassword), name).Scan(
-		&user.ID, &user.Email, &user.Name, &user.Role, &user.AvatarURL, &user.CreatedAt,
+		&user.ID, &user.Email, &user.Name, &user.Role, &user.AvatarURL, &user.EmailVerified, &user.CreatedAt,
 	)
 
 	if err != nil {
@@ -142,21 +155,23 @@ func register(c *gin.Context, pool *pgxpool.Pool) {
 		return
 	}
 
-	// Generate JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":   user.ID,
-		"email": user.Email,
-		"name":  user.Name,
-		"role":  user.Role,
-		"exp":   time.Now().Add(24 * 7 * time.Hour).Unix(),
-	})
-
-	tokenString, _ := token.SignedString(jwtSecret)
+	verify, err := prepareAccountEmail(c, tx, user.ID, tokenEmailVerify, mailLang(c, in.Lang), false)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	// ส่งลิงก์ยืนยันอีเมลหลัง commit; ส่งไม่ได้ก็ยังสมัครสำเร็จ (ขอส่งใหม่ที่ /auth/verify/resend)
+	verify.send(mailer)
+	if requireEmailVerification {
+		c.JSON(201, gin.H{"user": user, "email_verification_required": true})
+		return
+	}
 
-	c.JSON(201, gin.H{
-		"token": tokenString,
-		"user":  user,
-	})
+	startSession(c, pool, user, in.DeviceLabel, 201)
 }
 
 func getMe(c *gin.Context, pool *pgxpool.Pool) {
@@ -164,10 +179,10 @@ func getMe(c *gin.Context, pool *pgxpool.Pool) {
 
 	var user User
 	err := pool.QueryRow(c, `
-		SELECT id, email, name, role, avatar_url, created_at 
+		SELECT id, email, name, role, avatar_url, totp_enabled_at IS NOT NULL, email_verified_at IS NOT NULL, created_at 
 		FROM users WHERE id = $1
 	`, userID).Scan(
-		&user.ID, &user.Email, &user.Name, &user.Role, &user.AvatarURL, &user.CreatedAt,
+		&user.ID, &user.Email, &user.Name, &user.Role, &user.AvatarURL, &user.MFAEnabled, &user.EmailVerified, &user.CreatedAt,
 	)
 	if err != nil {
 		c.JSON(404, gin.H{"error": "user not found"})
@@ -177,13 +192,19 @@ func getMe(c *gin.Context, pool *pgxpool.Pool) {
 	c.JSON(200, user)
 }
 
-func logout(c *gin.Context) {
-	// JWT เป็น stateless ฝั่ง client ลบ token เอง
-	c.JSON(200, gin.H{"message": "logged out"})
+// Auth Middleware
+// token ต้องอ้าง session ที่ยังไม่ถูกยกเลิก (claim sid) role ใช้ค่าปัจจุบันใน DB
+func AuthMiddleware(pool *pgxpool.Pool) gin.HandlerFunc {
+	return authMiddleware(pool, false)
 }
 
-// Auth Middleware
-func AuthMiddleware() gin.HandlerFunc {
+// mfaSetupMiddleware เหมือน AuthMiddleware แต่ให้ user ที่ role บังคับ 2FA และยังไม่ได้เปิด
+// เข้าได้ (ใช้กับ route ตั้งค่า 2FA, /auth/me, logout)
+func mfaSetupMiddleware(pool *pgxpool.Pool) gin.HandlerFunc {
+	return authMiddleware(pool, true)
+}
+
+func authMiddleware(pool *pgxpool.Pool, allowMFASetup bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -200,6 +221,19 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// API key (jnk_...) ใช้ได้กับ route ทั่วไป แต่ไม่ใช้กับ route จัดการบัญชี/session/2FA
+		if strings.HasPrefix(tokenString, apiKeyPrefix) {
+			if allowMFASetup {
+				c.JSON(403, gin.H{"error": "this endpoint requires a login session"})
+				c.Abort()
+				return
+			}
+			if authenticateAPIKey(c, pool, tokenString) {
+				c.Next()
+			}
+			return
+		}
+
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			// ✅ กันโจมตีเปลี่ยน algorithm
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -240,7 +274,30 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		sid := ""
+		if v, ok := claims["sid"]; ok {
+			sid = strings.TrimSpace(fmt.Sprint(v))
+		}
+		if sid == "" {
+			c.JSON(401, gin.H{"error": "invalid token (no session)"})
+			c.Abort()
+			return
+		}
+		role, email, mfaPending, err := activeSession(c, pool, sid, userID, c.ClientIP())
+		if err != nil {
+			c.JSON(401, gin.H{"error": "session expired or revoked"})
+			c.Abort()
+			return
+		}
+		if mfaPending && !allowMFASetup {
+			c.JSON(403, gin.H{"error": "two-factor authentication is required for your role", "mfa_setup_required": true})
+			c.Abort()
+			return
+		}
+		userRole, userEmail = role, email
+
 		c.Set("userID", userID)
+		c.Set("sessionID", sid)
 		c.Set("userEmail", userEmail)
 		c.Set("userRole", userRole)
 		c.Next()
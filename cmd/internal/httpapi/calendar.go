@@ -0,0 +1,91 @@
+package httpapi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+// defaultCalendar คือระบบปีของวันที่ใน response เมื่อ request ไม่ได้ระบุ (DEFAULT_CALENDAR=ce|be)
+var defaultCalendar = calendarSetting("DEFAULT_CALENDAR")
+
+// docNoCalendar คือระบบปีในเลข doc_no ของ scheme ที่ไม่ได้กำหนด calendar (DOC_NO_CALENDAR=be → JG-2567-0001)
+var docNoCalendar = calendarSetting("DOC_NO_CALENDAR")
+
+func calendarSetting(key string) thai.Calendar {
+	cal, _ := thai.ParseCalendar(getEnv(key, "ce"))
+	return cal
+}
+
+// requestCalendar อ่านระบบปีจาก ?calendar= หรือ header X-Calendar (be / ce)
+// ใช้ทั้งวันที่ที่ส่งออกและวันที่ที่รับเข้า (ปีที่ไม่ได้เขียน พ.ศ./ค.ศ. กำกับ ดู thai.ParseDate)
+func requestCalendar(c *gin.Context) (thai.Calendar, error) {
+	v := c.Query("calendar")
+	if v == "" {
+		v = c.GetHeader("X-Calendar")
+	}
+	if v == "" {
+		return defaultCalendar, nil
+	}
+	cal, ok := thai.ParseCalendar(v)
+	if !ok {
+		return cal, errors.New("calendar must be be or ce")
+	}
+	return cal, nil
+}
+
+// calendarParam เหมือน requestCalendar แต่ตอบ 400 ให้เอง
+func calendarParam(c *gin.Context) (thai.Calendar, bool) {
+	cal, err := requestCalendar(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return cal, false
+	}
+	c.Header("X-Calendar", cal.String())
+	return cal, true
+}
+
+// parseDateInput แปลงวันที่ที่ผู้ใช้ส่งมา (ค.ศ./พ.ศ., เดือนไทย, เลขไทย) เป็น YYYY-MM-DD ค.ศ. สำหรับเก็บใน DB
+// ปีที่ไม่ได้กำกับระบบไว้อ่านตาม cal; ได้ปี ค.ศ. ตั้งแต่ 2400 แปลว่าส่ง พ.ศ. มาโดยไม่บอก จึงไม่รับ
+func parseDateInput(s string, cal thai.Calendar) (string, error) {
+	t, err := thai.ParseDate(strings.TrimSpace(s), cal)
+	if err != nil {
+		return "", err
+	}
+	if t.Year() >= thai.MinBEYear {
+		return "", errors.New("year " + strconv.Itoa(t.Year()) + " looks like a Buddhist Era year; send calendar=be")
+	}
+	return t.Format("2006-01-02"), nil
+}
+
+// localDate แปลงวันที่จาก DB (YYYY-MM-DD ค.ศ.) เป็นระบบปีที่ขอ
+func localDate(d *string, cal thai.Calendar) *string {
+	if d == nil || cal == thai.Gregorian {
+		return d
+	}
+	t, err := thai.ParseDate(*d, thai.Gregorian)
+	if err != nil {
+		return d
+	}
+	s := thai.FormatISO(t, cal)
+	return &s
+}
+
+func (j *Judgment) localize(cal thai.Calendar) {
+	j.JudgmentDate = localDate(j.JudgmentDate, cal)
+	j.calendar = cal
+}
+
+func (r *JudgmentRef) localize(cal thai.Calendar) {
+	r.JudgmentDate = localDate(r.JudgmentDate, cal)
+}
+
+func localizeRefs(refs []JudgmentRef, cal thai.Calendar) {
+	for i := range refs {
+		refs[i].localize(cal)
+	}
+}
@@ -0,0 +1,43 @@
+package httpapi
+
+import (
+	"testing"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+func TestParseDateInput(t *testing.T) {
+	tests := []struct {
+		in      string
+		cal     thai.Calendar
+		want    string
+		wantErr bool
+	}{
+		{"2024-03-15", thai.Gregorian, "2024-03-15", false},
+		{"2567-03-15", thai.Buddhist, "2024-03-15", false},
+		{"15 มี.ค. พ.ศ. ๒๕๖๗", thai.Gregorian, "2024-03-15", false},
+		{"2567-02-29", thai.Buddhist, "2024-02-29", false},
+		// ปี พ.ศ. ที่ส่งมาโดยไม่บอก calendar=be ไม่รับ (เดิมจะกลายเป็นปี ค.ศ. 2567)
+		{"2567-03-15", thai.Gregorian, "", true},
+		{"not a date", thai.Gregorian, "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseDateInput(tt.in, tt.cal)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseDateInput(%q, %v) = %q, %v; want %q, err=%v", tt.in, tt.cal, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLocalDate(t *testing.T) {
+	d := "2024-02-29"
+	if got := localDate(&d, thai.Buddhist); got == nil || *got != "2567-02-29" {
+		t.Errorf("localDate(be) = %v", got)
+	}
+	if got := localDate(&d, thai.Gregorian); got != &d {
+		t.Errorf("localDate(ce) should return input unchanged")
+	}
+	if got := localDate(nil, thai.Buddhist); got != nil {
+		t.Errorf("localDate(nil) = %v", *got)
+	}
+}
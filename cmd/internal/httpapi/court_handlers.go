@@ -0,0 +1,391 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Court struct {
+	ID            int       `json:"id"`
+	NameTH        string    `json:"name_th"`
+	NameEN        *string   `json:"name_en"`
+	Code          *string   `json:"code"` // รหัสสั้น ใช้ใน token {court} ของเลขเอกสาร
+	Level         string    `json:"level"`
+	Region        *string   `json:"region"`
+	ParentID      *int      `json:"parent_id"`
+	Aliases       []string  `json:"aliases"`
+	JudgmentCount int       `json:"judgment_count"` // ไม่นับ judgment ในถังขยะ
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type courtPayload struct {
+	NameTH   string   `json:"name_th"`
+	NameEN   *string  `json:"name_en"`
+	Code     *string  `json:"code"`
+	Level    string   `json:"level"`
+	Region   *string  `json:"region"`
+	ParentID *int     `json:"parent_id"`
+	Aliases  []string `json:"aliases"`
+}
+
+var courtLevels = []string{"supreme", "appeal", "first_instance"}
+
+var courtCodeRe = regexp.MustCompile(`^[A-Z0-9-]{1,10}$`)
+
+const courtColumns = `c.id, c.name_th, c.name_en, c.code, c.level, c.region, c.parent_id,
+       ARRAY(SELECT a.alias FROM court_aliases a WHERE a.court_id = c.id ORDER BY a.alias),
+       (SELECT COUNT(*) FROM judgments j WHERE j.court_id = c.id AND j.deleted_at IS NULL),
+       c.created_at, c.updated_at`
+
+func scanCourt(row pgx.Row) (Court, error) {
+	var ct Court
+	err := row.Scan(&ct.ID, &ct.NameTH, &ct.NameEN, &ct.Code, &ct.Level, &ct.Region, &ct.ParentID,
+		&ct.Aliases, &ct.JudgmentCount, &ct.CreatedAt, &ct.UpdatedAt)
+	return ct, err
+}
+
+func registerCourtRoutes(api, admin *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.GET("/courts", func(c *gin.Context) { listCourts(c, pool) })
+	api.GET("/courts/resolve", func(c *gin.Context) { resolveCourtHandler(c, pool) })
+	api.GET("/courts/:id", func(c *gin.Context) { getCourt(c, pool) })
+	admin.POST("/courts", func(c *gin.Context) { createCourt(c, pool) })
+	admin.PUT("/courts/:id", func(c *gin.Context) { updateCourt(c, pool) })
+	admin.DELETE("/courts/:id", func(c *gin.Context) { deleteCourt(c, pool) })
+}
+
+// listCourts: ?level=appeal&parent_id=1
+func listCourts(c *gin.Context, pool *pgxpool.Pool) {
+	conds := []string{"true"}
+	var args sqlArgs
+	if level := c.Query("level"); level != "" {
+		conds = append(conds, "c.level = "+args.add(level))
+	}
+	if parent := c.Query("parent_id"); parent != "" {
+		conds = append(conds, "c.parent_id = "+args.add(parent)+"::int")
+	}
+
+	rows, err := pool.Query(c, `
+SELECT `+courtColumns+`
+FROM courts c
+WHERE `+strings.Join(conds, " AND ")+`
+ORDER BY array_position(ARRAY['supreme','appeal','first_instance'], c.level), c.name_th`, args...)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	out := make([]Court, 0)
+	for rows.Next() {
+		ct, err := scanCourt(rows)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		out = append(out, ct)
+	}
+	c.JSON(200, out)
+}
+
+func getCourt(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	ct, err := loadCourt(c, pool, id)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(200, ct)
+}
+
+// resolveCourtHandler: GET /courts/resolve?name=ฎีกา
+func resolveCourtHandler(c *gin.Context, pool *pgxpool.Pool) {
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" {
+		c.JSON(400, gin.H{"error": "name is required"})
+		return
+	}
+	var id int
+	if err := pool.QueryRow(c, `SELECT resolve_court($1)`, name).Scan(&id); err != nil {
+		c.JSON(404, gin.H{"error": "unknown court: " + name})
+		return
+	}
+	ct, err := loadCourt(c, pool, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, ct)
+}
+
+func loadCourt(ctx context.Context, q querier, id int) (Court, error) {
+	return scanCourt(q.QueryRow(ctx, `SELECT `+courtColumns+` FROM courts c WHERE c.id=$1`, id))
+}
+
+func createCourt(c *gin.Context, pool *pgxpool.Pool) {
+	var in courtPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := validateCourtPayload(&in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	var id int
+	err = tx.QueryRow(c, `
+INSERT INTO courts (name_th, name_en, code, level, region, parent_id)
+VALUES ($1,$2,$3,$4,$5,$6)
+RETURNING id`, in.NameTH, in.NameEN, in.Code, in.Level, in.Region, in.ParentID).Scan(&id)
+	if err != nil {
+		courtWriteFailed(c, err)
+		return
+	}
+	saveCourt(c, pool, tx, id, in, 201)
+}
+
+func updateCourt(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var in courtPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := validateCourtPayload(&in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if in.ParentID != nil && *in.ParentID == id {
+		c.JSON(400, gin.H{"error": "a court cannot be its own parent"})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	// กัน parent วนกลับมาที่ตัวเอง
+	if in.ParentID != nil {
+		var cycle bool
+		err := tx.QueryRow(c, `
+WITH RECURSIVE up AS (
+  SELECT id, parent_id FROM courts WHERE id = $1
+  UNION
+  SELECT c.id, c.parent_id FROM courts c JOIN up ON c.id = up.parent_id
+)
+SELECT EXISTS (SELECT 1 FROM up WHERE id = $2)`, *in.ParentID, id).Scan(&cycle)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		if cycle {
+			c.JSON(400, gin.H{"error": "parent_id would create a cycle"})
+			return
+		}
+	}
+
+	ct, err := tx.Exec(c, `
+UPDATE courts SET name_th=$2, name_en=$3, code=$4, level=$5, region=$6, parent_id=$7, updated_at=now()
+WHERE id=$1`, id, in.NameTH, in.NameEN, in.Code, in.Level, in.Region, in.ParentID)
+	if err != nil {
+		courtWriteFailed(c, err)
+		return
+	}
+	if ct.RowsAffected() == 0 {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if _, err := tx.Exec(c, `DELETE FROM court_aliases WHERE court_id=$1`, id); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	saveCourt(c, pool, tx, id, in, 200)
+}
+
+// saveCourt บันทึก alias, ผูก judgment ที่ชื่อศาลตรงกับศาลนี้ แล้ว commit
+func saveCourt(c *gin.Context, pool *pgxpool.Pool, tx pgx.Tx, id int, in courtPayload, status int) {
+	for _, a := range in.Aliases {
+		if _, err := tx.Exec(c, `INSERT INTO court_aliases (court_id, alias) VALUES ($1,$2)`, id, a); err != nil {
+			courtWriteFailed(c, err)
+			return
+		}
+	}
+	relinked, err := relinkCourtJudgments(c, tx, id, c.GetString("userID"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	ct, err := loadCourt(c, tx, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if relinked > 0 {
+		// ชื่อศาลใน judgment เปลี่ยน: index ใหม่เบื้องหลัง
+		requestReindex()
+	}
+	c.JSON(status, ct)
+}
+
+// relinkCourtJudgments ผูก judgment ที่ยังไม่มีศาลแต่ชื่อตรงกับศาลนี้ และปรับชื่อศาลใน judgment
+// ให้เป็นชื่อมาตรฐานปัจจุบัน แถวที่เปลี่ยนขึ้น version และเก็บ revision เหมือนแก้ทีละเรื่อง
+// (จะถูก reindex ภายหลัง)
+func relinkCourtJudgments(ctx context.Context, tx pgx.Tx, id int, userID string) (int64, error) {
+	rows, err := tx.Query(ctx, `
+UPDATE judgments j
+SET court_id = c.id, court = c.name_th, index_version = 0,
+    version = j.version + 1, updated_at = now(), updated_by = $2
+FROM courts c
+WHERE c.id = $1
+  AND (j.court_id = c.id OR (j.court_id IS NULL AND j.court IS NOT NULL AND resolve_court(j.court) = c.id))
+  AND (j.court_id IS DISTINCT FROM c.id OR j.court IS DISTINCT FROM c.name_th)
+RETURNING j.id::text`, id, nullIfEmpty(userID))
+	if err != nil {
+		return 0, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+	if err := recordRevisions(ctx, tx, ids, "update", userID); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// deleteCourt: ลบได้เฉพาะศาลที่ไม่มี judgment (รวมในถังขยะ) และไม่มีศาลลูก
+func deleteCourt(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var inUse bool
+	err = pool.QueryRow(c, `
+SELECT EXISTS (SELECT 1 FROM judgments WHERE court_id=$1)
+    OR EXISTS (SELECT 1 FROM courts WHERE parent_id=$1)`, id).Scan(&inUse)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if inUse {
+		c.JSON(409, gin.H{"error": "court is used by judgments or other courts"})
+		return
+	}
+	ct, err := pool.Exec(c, `DELETE FROM courts WHERE id=$1`, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if ct.RowsAffected() == 0 {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	c.Status(204)
+}
+
+func validateCourtPayload(in *courtPayload) error {
+	in.NameTH = strings.TrimSpace(in.NameTH)
+	if in.NameTH == "" {
+		return errors.New("name_th is required")
+	}
+	if in.NameEN != nil {
+		if s := strings.TrimSpace(*in.NameEN); s == "" {
+			in.NameEN = nil
+		} else {
+			in.NameEN = &s
+		}
+	}
+	if in.Code != nil {
+		if s := strings.ToUpper(strings.TrimSpace(*in.Code)); s == "" {
+			in.Code = nil
+		} else if !courtCodeRe.MatchString(s) {
+			return errors.New("code must be 1-10 letters, digits or '-'")
+		} else {
+			in.Code = &s
+		}
+	}
+	if in.Region != nil {
+		if s := strings.TrimSpace(*in.Region); s == "" {
+			in.Region = nil
+		} else {
+			in.Region = &s
+		}
+	}
+	if in.Level == "" {
+		in.Level = "first_instance"
+	}
+	if !containsString(courtLevels, in.Level) {
+		return errors.New("level must be one of " + strings.Join(courtLevels, ", "))
+	}
+	aliases := []string{}
+	for _, a := range in.Aliases {
+		if a = strings.TrimSpace(a); a != "" && !containsString(aliases, a) {
+			aliases = append(aliases, a)
+		}
+	}
+	in.Aliases = aliases
+	return nil
+}
+
+func courtWriteFailed(c *gin.Context, err error) {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "duplicate"):
+		c.JSON(409, gin.H{"error": "court name, code or alias already in use"})
+	case strings.Contains(msg, "foreign key"):
+		c.JSON(400, gin.H{"error": "parent court not found"})
+	default:
+		c.JSON(500, gin.H{"error": err.Error()})
+	}
+}
+
+// resolveCourt แปลงชื่อ/alias เป็น court id และชื่อมาตรฐาน
+// ชื่อที่ไม่รู้จักเก็บตามที่ส่งมา (court_id เป็น null) เพื่อให้ admin เพิ่ม alias ภายหลังได้
+func resolveCourt(ctx context.Context, q querier, name *string) (*int, *string, error) {
+	if name == nil || strings.TrimSpace(*name) == "" {
+		return nil, name, nil
+	}
+	var id int
+	var canonical string
+	err := q.QueryRow(ctx, `SELECT id, name_th FROM courts WHERE id = resolve_court($1)`, *name).Scan(&id, &canonical)
+	if errors.Is(err, pgx.ErrNoRows) {
+		s := strings.TrimSpace(*name)
+		return nil, &s, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &id, &canonical, nil
+}
@@ -0,0 +1,404 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/docno"
+	"judgment-notes/cmd/internal/thai"
+)
+
+// DocNoScheme คือรูปแบบเลขเอกสารของ judgment ที่ court/category ตรงกัน (ไม่ระบุ = ใช้ได้ทุกค่า)
+type DocNoScheme struct {
+	ID         int       `json:"id"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	Template   string    `json:"template"` // เช่น "{court}-{category}-{year}/{seq}"
+	Padding    int       `json:"padding"`
+	Reset      string    `json:"reset"`       // yearly | never
+	YearSource string    `json:"year_source"` // created | judgment_date
+	Calendar   *string   `json:"calendar"`    // ce | be, null = ตาม DOC_NO_CALENDAR
+	CourtID    *int      `json:"court_id"`
+	Category   *string   `json:"category"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type docNoSchemePayload struct {
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Template   string  `json:"template"`
+	Padding    *int    `json:"padding"`
+	Reset      string  `json:"reset"`
+	YearSource string  `json:"year_source"`
+	Calendar   *string `json:"calendar"`
+	CourtID    *int    `json:"court_id"`
+	Category   *string `json:"category"`
+}
+
+// defaultDocNoScheme ใช้เมื่อไม่มี scheme อื่นตรง: ลบไม่ได้และต้องไม่จำกัด court/category
+const defaultDocNoScheme = "default"
+
+const docNoSchemeColumns = `s.id, s.code, s.name, s.template, s.padding, s.reset, s.year_source,
+       s.calendar, s.court_id, s.category, s.created_at, s.updated_at`
+
+func scanDocNoScheme(row pgx.Row) (DocNoScheme, error) {
+	var s DocNoScheme
+	err := row.Scan(&s.ID, &s.Code, &s.Name, &s.Template, &s.Padding, &s.Reset, &s.YearSource,
+		&s.Calendar, &s.CourtID, &s.Category, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func registerDocNoSchemeRoutes(auth, admin *gin.RouterGroup, pool *pgxpool.Pool) {
+	auth.GET("/doc-no-schemes/preview", func(c *gin.Context) { previewDocNo(c, pool) })
+	admin.GET("/doc-no-schemes", func(c *gin.Context) { listDocNoSchemes(c, pool) })
+	admin.POST("/doc-no-schemes", func(c *gin.Context) { createDocNoScheme(c, pool) })
+	admin.PUT("/doc-no-schemes/:id", func(c *gin.Context) { updateDocNoScheme(c, pool) })
+	admin.DELETE("/doc-no-schemes/:id", func(c *gin.Context) { deleteDocNoScheme(c, pool) })
+}
+
+func listDocNoSchemes(c *gin.Context, pool *pgxpool.Pool) {
+	rows, err := pool.Query(c, `SELECT `+docNoSchemeColumns+` FROM doc_no_schemes s ORDER BY s.code`)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	out := make([]DocNoScheme, 0)
+	for rows.Next() {
+		s, err := scanDocNoScheme(rows)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		out = append(out, s)
+	}
+	c.JSON(200, out)
+}
+
+func createDocNoScheme(c *gin.Context, pool *pgxpool.Pool) {
+	var in docNoSchemePayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := validateDocNoScheme(&in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err := checkDocNoSchemeCourt(c, pool, in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := scanDocNoScheme(pool.QueryRow(c, `
+INSERT INTO doc_no_schemes AS s (code, name, template, padding, reset, year_source, calendar, court_id, category)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+RETURNING `+docNoSchemeColumns,
+		in.Code, in.Name, in.Template, *in.Padding, in.Reset, in.YearSource, in.Calendar, in.CourtID, in.Category))
+	if err != nil {
+		docNoSchemeWriteFailed(c, err)
+		return
+	}
+	c.JSON(201, s)
+}
+
+// updateDocNoScheme: ตัวนับเดิมยังใช้ต่อ (เปลี่ยน reset/calendar แล้วรอบของตัวนับจะเปลี่ยนตาม)
+func updateDocNoScheme(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var in docNoSchemePayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := validateDocNoScheme(&in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	var current string
+	if err := pool.QueryRow(c, `SELECT code FROM doc_no_schemes WHERE id=$1`, id).Scan(&current); err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if current == defaultDocNoScheme && in.Code != defaultDocNoScheme {
+		c.JSON(400, gin.H{"error": "the default scheme cannot be renamed"})
+		return
+	}
+	if err := checkDocNoSchemeCourt(c, pool, in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	s, err := scanDocNoScheme(pool.QueryRow(c, `
+UPDATE doc_no_schemes AS s
+SET code=$2, name=$3, template=$4, padding=$5, reset=$6, year_source=$7, calendar=$8,
+    court_id=$9, category=$10, updated_at=now()
+WHERE s.id=$1
+RETURNING `+docNoSchemeColumns,
+		id, in.Code, in.Name, in.Template, *in.Padding, in.Reset, in.YearSource, in.Calendar, in.CourtID, in.Category))
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if err != nil {
+		docNoSchemeWriteFailed(c, err)
+		return
+	}
+	c.JSON(200, s)
+}
+
+// deleteDocNoScheme: ตัวนับของ scheme ถูกลบตาม, เลขที่ออกไปแล้วไม่เปลี่ยน
+func deleteDocNoScheme(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var code string
+	if err := pool.QueryRow(c, `SELECT code FROM doc_no_schemes WHERE id=$1`, id).Scan(&code); err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if code == defaultDocNoScheme {
+		c.JSON(409, gin.H{"error": "the default scheme cannot be deleted"})
+		return
+	}
+	if _, err := pool.Exec(c, `DELETE FROM doc_no_schemes WHERE id=$1`, id); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(204)
+}
+
+// previewDocNo: GET /doc-no-schemes/preview?court=ศาลฎีกา&category=tax&judgment_date=2567-03-01
+// ดูเลขถัดไปโดยไม่ใช้ตัวนับ (?scheme=code บังคับใช้ scheme นั้น) ผู้ใช้อื่นอาจได้เลขนี้ไปก่อน
+func previewDocNo(c *gin.Context, pool *pgxpool.Pool) {
+	court := strings.TrimSpace(c.Query("court"))
+	courtID, _, err := resolveCourt(c, pool, &court)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	var category *string
+	if s := strings.TrimSpace(c.Query("category")); s != "" {
+		category = &s
+	}
+	var judgmentDate *string
+	if s := c.Query("judgment_date"); s != "" {
+		cal, err := requestCalendar(c)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		iso, err := parseDateInput(s, cal)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "judgment_date: " + err.Error()})
+			return
+		}
+		judgmentDate = &iso
+	}
+
+	var s DocNoScheme
+	if code := c.Query("scheme"); code != "" {
+		s, err = scanDocNoScheme(pool.QueryRow(c, `SELECT `+docNoSchemeColumns+` FROM doc_no_schemes s WHERE s.code=$1`, code))
+	} else {
+		s, err = pickDocNoScheme(c, pool, courtID, category)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(404, gin.H{"error": "doc number scheme not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	docNo, err := formatDocNo(c, pool, s, courtID, category, judgmentDate, false)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"doc_no": docNo, "scheme": s})
+}
+
+// pickDocNoScheme เลือก scheme ที่เจาะจงที่สุด: court+category > category > court > default
+func pickDocNoScheme(ctx context.Context, q querier, courtID *int, category *string) (DocNoScheme, error) {
+	return scanDocNoScheme(q.QueryRow(ctx, `
+SELECT `+docNoSchemeColumns+`
+FROM doc_no_schemes s
+WHERE (s.court_id IS NULL OR s.court_id = $1)
+  AND (s.category IS NULL OR lower(s.category) = lower($2))
+ORDER BY (s.category IS NOT NULL) DESC, (s.court_id IS NOT NULL) DESC, s.id
+LIMIT 1`, courtID, category))
+}
+
+// nextDocNo ออกเลข doc_no สำหรับ judgment ใหม่ (consume=false ดูเลขถัดไปโดยไม่ใช้ตัวนับ)
+func nextDocNo(ctx context.Context, q querier, courtID *int, category, judgmentDate *string, consume bool) (string, DocNoScheme, error) {
+	s, err := pickDocNoScheme(ctx, q, courtID, category)
+	if err != nil {
+		return "", s, err
+	}
+	docNo, err := formatDocNo(ctx, q, s, courtID, category, judgmentDate, consume)
+	return docNo, s, err
+}
+
+func formatDocNo(ctx context.Context, q querier, s DocNoScheme, courtID *int, category, judgmentDate *string, consume bool) (string, error) {
+	t := time.Now()
+	if s.YearSource == "judgment_date" && judgmentDate != nil {
+		if d, err := time.Parse("2006-01-02", *judgmentDate); err == nil {
+			t = d
+		}
+	}
+	cal := docNoCalendar
+	if s.Calendar != nil {
+		cal, _ = thai.ParseCalendar(*s.Calendar)
+	}
+	year := t.Year()
+	if cal == thai.Buddhist {
+		year += thai.BEOffset
+	}
+	period := docno.Period(s.Reset, year)
+
+	v := docno.Values{Year: year}
+	if category != nil {
+		v.Category = *category
+	}
+	if courtID != nil {
+		var code *string
+		if err := q.QueryRow(ctx, `SELECT code FROM courts WHERE id=$1`, *courtID).Scan(&code); err != nil {
+			return "", err
+		}
+		if code != nil {
+			v.Court = *code
+		}
+	}
+
+	// scheme อื่นออกเลขหน้าตาเดียวกันได้ (template ซ้ำกัน, ศาลไม่มีรหัสเป็น X เหมือนกัน, scheme ที่ลบแล้วสร้างใหม่)
+	// doc_no เป็น UNIQUE จึงข้ามเลขที่ถูกใช้ไปแล้ว ไม่งั้น insert พังด้วยเลขเดิมทุกครั้ง
+	var last int
+	if !consume {
+		if err := q.QueryRow(ctx, `
+SELECT COALESCE((SELECT last_no FROM judgment_doc_counters WHERE scheme_id=$1 AND year=$2), 0)`,
+			s.ID, period).Scan(&last); err != nil {
+			return "", err
+		}
+	}
+	for i := 1; i <= maxDocNoSkips; i++ {
+		if consume {
+			if err := q.QueryRow(ctx, `
+INSERT INTO judgment_doc_counters (scheme_id, year, last_no) VALUES ($1,$2,1)
+ON CONFLICT (scheme_id, year) DO UPDATE SET last_no = judgment_doc_counters.last_no + 1
+RETURNING last_no`, s.ID, period).Scan(&v.Seq); err != nil {
+				return "", err
+			}
+		} else {
+			v.Seq = last + i
+		}
+		docNo := docno.Format(s.Template, s.Padding, v)
+		var taken bool
+		if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM judgments WHERE doc_no = $1)`, docNo).Scan(&taken); err != nil {
+			return "", err
+		}
+		if !taken {
+			return docNo, nil
+		}
+	}
+	return "", errors.New("doc number scheme " + s.Code + ": no free number after " + strconv.Itoa(maxDocNoSkips) +
+		" tries; raise its counter or change the template")
+}
+
+// จำนวนเลขที่ถูกใช้ไปแล้วที่ยอมข้ามต่อการออกเลขหนึ่งครั้ง
+const maxDocNoSkips = 1000
+
+// checkDocNoSchemeCourt: scheme เฉพาะศาลที่ใช้ {court} ศาลนั้นต้องมีรหัส (ไม่งั้นทุกศาลที่ไม่มีรหัสได้ X เหมือนกัน)
+func checkDocNoSchemeCourt(ctx context.Context, q querier, in docNoSchemePayload) error {
+	if in.CourtID == nil || !strings.Contains(in.Template, "{court}") {
+		return nil
+	}
+	var code *string
+	err := q.QueryRow(ctx, `SELECT code FROM courts WHERE id=$1`, *in.CourtID).Scan(&code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("court not found")
+	}
+	if err != nil {
+		return err
+	}
+	if code == nil || strings.TrimSpace(*code) == "" {
+		return errors.New("the court has no code; set a court code before using {court} in its scheme")
+	}
+	return nil
+}
+
+func validateDocNoScheme(in *docNoSchemePayload) error {
+	in.Code = strings.TrimSpace(in.Code)
+	in.Name = strings.TrimSpace(in.Name)
+	in.Template = strings.TrimSpace(in.Template)
+	if in.Code == "" {
+		return errors.New("code is required")
+	}
+	if in.Name == "" {
+		in.Name = in.Code
+	}
+	if in.Padding == nil {
+		p := 4
+		in.Padding = &p
+	}
+	if in.Reset == "" {
+		in.Reset = docno.ResetYearly
+	}
+	if in.YearSource == "" {
+		in.YearSource = "created"
+	}
+	if in.YearSource != "created" && in.YearSource != "judgment_date" {
+		return errors.New("year_source must be created or judgment_date")
+	}
+	if err := docno.Validate(in.Template, in.Reset, *in.Padding); err != nil {
+		return err
+	}
+	if in.Calendar != nil {
+		cal, ok := thai.ParseCalendar(*in.Calendar)
+		if !ok {
+			return errors.New("calendar must be be or ce")
+		}
+		s := cal.String()
+		in.Calendar = &s
+	}
+	if in.Category != nil {
+		if s := strings.TrimSpace(*in.Category); s == "" {
+			in.Category = nil
+		} else {
+			in.Category = &s
+		}
+	}
+	if in.Code == defaultDocNoScheme && (in.CourtID != nil || in.Category != nil) {
+		return errors.New("the default scheme cannot be limited to a court or category")
+	}
+	return nil
+}
+
+func docNoSchemeWriteFailed(c *gin.Context, err error) {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "idx_doc_no_schemes_scope"):
+		c.JSON(409, gin.H{"error": "another scheme already covers this court and category"})
+	case strings.Contains(msg, "duplicate"):
+		c.JSON(409, gin.H{"error": "scheme code already in use"})
+	case strings.Contains(msg, "foreign key"):
+		c.JSON(400, gin.H{"error": "court not found"})
+	default:
+		c.JSON(500, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,107 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// docNoQuerier จำลองตัวนับ, รหัสศาล และ doc_no ที่ถูกใช้แล้ว
+type docNoQuerier struct {
+	counter int
+	courts  map[int]*string
+	taken   map[string]bool
+}
+
+func (q *docNoQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected Exec")
+}
+
+func (q *docNoQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, errors.New("unexpected Query")
+}
+
+func (q *docNoQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	switch {
+	case strings.Contains(sql, "INSERT INTO judgment_doc_counters"):
+		q.counter++
+		return fakeRow{vals: []any{q.counter}}
+	case strings.Contains(sql, "FROM judgment_doc_counters"):
+		return fakeRow{vals: []any{q.counter}}
+	case strings.Contains(sql, "FROM courts"):
+		code, ok := q.courts[args[0].(int)]
+		if !ok {
+			return fakeRow{err: pgx.ErrNoRows}
+		}
+		return fakeRow{vals: []any{code}}
+	case strings.Contains(sql, "FROM judgments"):
+		return fakeRow{vals: []any{q.taken[args[0].(string)]}}
+	}
+	return fakeRow{err: errors.New("unexpected QueryRow: " + sql)}
+}
+
+func TestFormatDocNoSkipsTakenNumbers(t *testing.T) {
+	ce := "ce"
+	s := DocNoScheme{ID: 1, Code: "default", Template: "JG-{year}-{seq}", Padding: 4, Reset: "yearly", YearSource: "judgment_date", Calendar: &ce}
+	date := "2024-05-01"
+	// scheme ที่ลบแล้วสร้างใหม่: ตัวนับเริ่มที่ 1 แต่เลข 1-2 ถูกใช้ไปแล้ว
+	q := &docNoQuerier{taken: map[string]bool{"JG-2024-0001": true, "JG-2024-0002": true}}
+
+	got, err := formatDocNo(context.Background(), q, s, nil, nil, &date, false)
+	if err != nil || got != "JG-2024-0003" {
+		t.Fatalf("preview = %q, %v; want JG-2024-0003", got, err)
+	}
+	if q.counter != 0 {
+		t.Fatalf("preview consumed the counter: %d", q.counter)
+	}
+
+	got, err = formatDocNo(context.Background(), q, s, nil, nil, &date, true)
+	if err != nil || got != "JG-2024-0003" {
+		t.Fatalf("consume = %q, %v; want JG-2024-0003", got, err)
+	}
+	if q.counter != 3 {
+		t.Fatalf("counter = %d, want 3", q.counter)
+	}
+}
+
+func TestFormatDocNoGivesUp(t *testing.T) {
+	s := DocNoScheme{ID: 1, Code: "flat", Template: "{court}-{seq}", Padding: 1, Reset: "never", YearSource: "created"}
+	q := &docNoQuerier{taken: map[string]bool{}}
+	for i := 1; i <= maxDocNoSkips; i++ {
+		q.taken["X-"+strconv.Itoa(i)] = true
+	}
+	if _, err := formatDocNo(context.Background(), q, s, nil, nil, nil, true); err == nil {
+		t.Fatal("expected an error when every number is taken")
+	}
+}
+
+func TestCheckDocNoSchemeCourt(t *testing.T) {
+	code, blank := "SC", ""
+	q := &docNoQuerier{courts: map[int]*string{1: &code, 2: nil, 3: &blank}}
+	id := func(n int) *int { return &n }
+	tests := []struct {
+		template string
+		courtID  *int
+		ok       bool
+	}{
+		{"{court}-{seq}", id(1), true},
+		{"{court}-{seq}", id(2), false},
+		{"{court}-{seq}", id(3), false},
+		{"{court}-{seq}", id(9), false},
+		// ไม่ใช้ {court} ศาลไม่ต้องมีรหัส
+		{"A-{seq}", id(2), true},
+		// scheme ที่ไม่จำกัดศาลใช้ {court} ได้ (ศาลที่ไม่มีรหัสได้ X)
+		{"{court}-{seq}", nil, true},
+	}
+	for _, tt := range tests {
+		err := checkDocNoSchemeCourt(context.Background(), q, docNoSchemePayload{Template: tt.template, CourtID: tt.courtID})
+		if (err == nil) != tt.ok {
+			t.Errorf("checkDocNoSchemeCourt(%q, %v) = %v, want ok=%v", tt.template, tt.courtID, err, tt.ok)
+		}
+	}
+}
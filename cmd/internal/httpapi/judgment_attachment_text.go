@@ -0,0 +1,290 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/extract"
+	"judgment-notes/cmd/internal/search"
+	"judgment-notes/cmd/internal/storage"
+)
+
+// ความยาวข้อความสูงสุดที่เก็บต่อไฟล์ (EXTRACT_MAX_RUNES)
+var extractMaxRunes = envInt("EXTRACT_MAX_RUNES", 1_000_000)
+
+const (
+	extractMaxAttempts = 3
+	extractStaleAfter  = "15 minutes" // งานที่ค้างสถานะ processing นานกว่านี้ถือว่า worker ตายไปแล้ว
+)
+
+// AttachmentMatch คือไฟล์แนบที่ตรงคำค้น (แสดงใน listJudgments)
+type AttachmentMatch struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Snippet  string `json:"snippet,omitempty"`
+}
+
+func registerAttachmentTextRoutes(api, auth *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.GET("/judgments/:id/attachments/:attachmentId/text", func(c *gin.Context) { getAttachmentText(c, pool) })
+	auth.POST("/judgments/:id/attachments/:attachmentId/extract", func(c *gin.Context) { requeueAttachmentText(c, pool) })
+}
+
+// getAttachmentText: ข้อความที่ดึงได้ (text/plain)
+func getAttachmentText(c *gin.Context, pool *pgxpool.Pool) {
+	var status string
+	var text *string
+	err := pool.QueryRow(c, `
+SELECT a.extract_status, a.content_text
+FROM judgment_attachments a
+JOIN judgments j ON j.id = a.judgment_id AND j.deleted_at IS NULL
+WHERE a.judgment_id=$1 AND a.id=$2`, c.Param("id"), c.Param("attachmentId")).Scan(&status, &text)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if text == nil {
+		c.JSON(409, gin.H{"error": "text is not available", "extract_status": status})
+		return
+	}
+	c.Data(200, "text/plain; charset=utf-8", []byte(*text))
+}
+
+// requeueAttachmentText สั่งดึงข้อความใหม่ (เช่น หลังแก้ปัญหาที่ทำให้ failed)
+func requeueAttachmentText(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	if !authorizeJudgmentEdit(c, pool, id) {
+		return
+	}
+	ct, err := pool.Exec(c, `
+UPDATE judgment_attachments
+SET extract_status='pending', extract_error=NULL, extract_attempts=0
+WHERE judgment_id=$1 AND id=$2`, id, c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if ct.RowsAffected() == 0 {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	wakeExtractor()
+	c.JSON(202, gin.H{"extract_status": "pending"})
+}
+
+// attachAttachmentMatches ใส่ไฟล์แนบที่ตรงคำค้นให้ judgment แต่ละรายการ (query เดียวทั้งหน้า)
+func attachAttachmentMatches(ctx context.Context, pool *pgxpool.Pool, items []Judgment, jq judgmentQuery) error {
+	ids := make([]string, len(items))
+	byID := map[string]*Judgment{}
+	for i := range items {
+		ids[i] = items[i].ID
+		byID[items[i].ID] = &items[i]
+	}
+
+	rows, err := pool.Query(ctx, `
+SELECT a.judgment_id, a.id, a.filename, COALESCE(a.content_text, '')
+FROM judgment_attachments a
+WHERE a.judgment_id = ANY($1::uuid[]) AND a.search_vector @@ $2::tsquery
+ORDER BY ts_rank_cd(a.search_vector, $2::tsquery) DESC, a.created_at`, ids, jq.TSQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var judgmentID, text string
+		var m AttachmentMatch
+		if err := rows.Scan(&judgmentID, &m.ID, &m.Filename, &text); err != nil {
+			return err
+		}
+		m.Snippet, _ = search.Highlight(text, jq.Terms, snippetRunes)
+		j := byID[judgmentID]
+		j.MatchedAttachments = append(j.MatchedAttachments, m)
+	}
+	return rows.Err()
+}
+
+// ---- background worker ----
+
+var extractWake = make(chan struct{}, 1)
+
+// wakeExtractor ปลุก worker ทันที (เช่น หลังอัปโหลด) ไม่ต้องรอรอบ poll
+func wakeExtractor() {
+	select {
+	case extractWake <- struct{}{}:
+	default:
+	}
+}
+
+// StartAttachmentExtractor ดึงข้อความจากไฟล์แนบที่ยังไม่ได้ทำ แล้วสร้าง search_vector
+// รันได้หลาย instance พร้อมกัน (จองงานด้วย FOR UPDATE SKIP LOCKED)
+// EXTRACT_POLL_INTERVAL (duration, ค่าเริ่มต้น 30s)
+func StartAttachmentExtractor(ctx context.Context, pool *pgxpool.Pool, store storage.Store) {
+	interval, err := time.ParseDuration(getEnv("EXTRACT_POLL_INTERVAL", "30s"))
+	if err != nil || interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	go func() {
+		for {
+			if err := failStaleExtractions(ctx, pool); err != nil {
+				log.Printf("extract: %v", err)
+			}
+			for {
+				ok, err := extractNextAttachment(ctx, pool, store)
+				if err != nil {
+					log.Printf("extract: %v", err)
+					break
+				}
+				if !ok {
+					break
+				}
+			}
+			if err := reindexAttachments(ctx, pool); err != nil {
+				log.Printf("extract reindex: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-extractWake:
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// failStaleExtractions: งานที่ค้างและลองครบจำนวนครั้งแล้ว ถือว่าล้มเหลว (กันไฟล์ที่ทำให้ worker ล่มวนไม่จบ)
+func failStaleExtractions(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx, `
+UPDATE judgment_attachments
+SET extract_status='failed', extract_error='extraction did not finish'
+WHERE extract_status='processing' AND extract_started_at < now() - interval '`+extractStaleAfter+`'
+  AND extract_attempts >= $1`, extractMaxAttempts)
+	return err
+}
+
+// extractNextAttachment จองงานหนึ่งชิ้นแล้วทำให้เสร็จ คืน false ถ้าไม่มีงานเหลือ
+func extractNextAttachment(ctx context.Context, pool *pgxpool.Pool, store storage.Store) (bool, error) {
+	var id, key, contentType string
+	err := pool.QueryRow(ctx, `
+UPDATE judgment_attachments
+SET extract_status='processing', extract_started_at=now(), extract_attempts=extract_attempts+1
+WHERE id = (
+  SELECT id FROM judgment_attachments
+  WHERE extract_status='pending'
+     OR (extract_status='processing' AND extract_started_at < now() - interval '`+extractStaleAfter+`' AND extract_attempts < $1)
+  ORDER BY created_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, storage_key, content_type`, extractMaxAttempts).Scan(&id, &key, &contentType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	status, text, extractErr, err := extractAttachment(ctx, store, key, contentType)
+	if err != nil {
+		// อ่านไฟล์จาก storage ไม่ได้: ปล่อยสถานะ processing ไว้ จะถูกลองใหม่เมื่อครบเวลา
+		return false, errors.New(key + ": " + err.Error())
+	}
+
+	var content, vector *string
+	if status == "done" {
+		vec := search.Vector(search.Field{Text: text, Weight: search.WeightD})
+		content, vector = &text, &vec
+	}
+	_, err = pool.Exec(ctx, `
+UPDATE judgment_attachments
+SET extract_status=$2, extract_error=$3, content_text=$4, search_vector=$5::tsvector,
+    index_version=$6, extracted_at=now()
+WHERE id=$1`, id, status, nullIfEmpty(extractErr), content, vector, judgmentIndexVersion)
+	return true, err
+}
+
+// extractAttachment คืนสถานะและข้อความ; err คือปัญหาชั่วคราว (storage) ที่ควรลองใหม่
+func extractAttachment(ctx context.Context, store storage.Store, key, contentType string) (status, text, extractErr string, err error) {
+	if !extract.Supported(contentType) {
+		return "unsupported", "", "", nil
+	}
+
+	obj, err := store.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "failed", "", "file is missing from storage", nil
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+	defer obj.Close()
+
+	// library อ่าน PDF/DOCX ต้องใช้ ReaderAt จึงคัดลอกลงไฟล์ชั่วคราวก่อน
+	tmp, err := os.CreateTemp("", "extract-*")
+	if err != nil {
+		return "", "", "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	size, err := io.Copy(tmp, obj)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	text, err = extract.Text(tmp, size, contentType)
+	if err != nil {
+		return "failed", "", err.Error(), nil
+	}
+	if strings.TrimSpace(text) == "" {
+		return "empty", "", "no text layer (scanned document?)", nil
+	}
+	if utf8.RuneCountInString(text) > extractMaxRunes {
+		text = string([]rune(text)[:extractMaxRunes])
+	}
+	return "done", text, "", nil
+}
+
+// reindexAttachments สร้าง search_vector ใหม่จากข้อความที่เก็บไว้ เมื่อวิธีสร้าง index เปลี่ยน
+func reindexAttachments(ctx context.Context, pool *pgxpool.Pool) error {
+	for {
+		rows, err := pool.Query(ctx, `
+SELECT id, content_text FROM judgment_attachments
+WHERE extract_status='done' AND index_version < $1
+LIMIT 50`, judgmentIndexVersion)
+		if err != nil {
+			return err
+		}
+		type item struct{ id, text string }
+		items := []item{}
+		for rows.Next() {
+			var it item
+			if err := rows.Scan(&it.id, &it.text); err != nil {
+				rows.Close()
+				return err
+			}
+			items = append(items, it)
+		}
+		rows.Close()
+		if len(items) == 0 {
+			return nil
+		}
+
+		for _, it := range items {
+			vec := search.Vector(search.Field{Text: it.text, Weight: search.WeightD})
+			if _, err := pool.Exec(ctx, `UPDATE judgment_attachments SET search_vector=$2::tsvector, index_version=$3 WHERE id=$1`,
+				it.id, vec, judgmentIndexVersion); err != nil {
+				return err
+			}
+		}
+	}
+}
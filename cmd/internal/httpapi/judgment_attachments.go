@@ -0,0 +1,328 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/storage"
+)
+
+// ขนาดไฟล์แนบสูงสุด (ATTACHMENT_MAX_BYTES, ค่าเริ่มต้น 50MB)
+var attachmentMaxBytes = int64(envInt("ATTACHMENT_MAX_BYTES", 50<<20))
+
+// ชนิดไฟล์ที่อนุญาต ตรวจจากเนื้อไฟล์จริง ไม่เชื่อนามสกุลหรือ Content-Type ที่ client ส่งมา (ATTACHMENT_TYPES คั่นด้วย ,)
+var attachmentTypes = strings.Split(getEnv("ATTACHMENT_TYPES",
+	"application/pdf,image/jpeg,image/png,image/tiff,text/plain,"+
+		"application/msword,application/vnd.openxmlformats-officedocument.wordprocessingml.document"), ",")
+
+type Attachment struct {
+	ID             string    `json:"id"`
+	JudgmentID     string    `json:"judgment_id"`
+	Filename       string    `json:"filename"`
+	ContentType    string    `json:"content_type"`
+	Size           int64     `json:"size"`
+	SHA256         string    `json:"sha256"`
+	UploadedBy     *string   `json:"uploaded_by"`
+	UploadedByName *string   `json:"uploaded_by_name"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// สถานะการดึงข้อความสำหรับค้นหา (pending / processing / done / empty / unsupported / failed)
+	ExtractStatus string     `json:"extract_status"`
+	ExtractError  *string    `json:"extract_error,omitempty"`
+	ExtractedAt   *time.Time `json:"extracted_at,omitempty"`
+
+	storageKey string
+}
+
+const attachmentColumns = `a.id, a.judgment_id, a.filename, a.content_type, a.size_bytes, a.sha256,
+       a.uploaded_by, (SELECT u.name FROM users u WHERE u.id = a.uploaded_by), a.created_at,
+       a.extract_status, a.extract_error, a.extracted_at, a.storage_key`
+
+func scanAttachment(row pgx.Row) (Attachment, error) {
+	var a Attachment
+	err := row.Scan(&a.ID, &a.JudgmentID, &a.Filename, &a.ContentType, &a.Size, &a.SHA256,
+		&a.UploadedBy, &a.UploadedByName, &a.CreatedAt,
+		&a.ExtractStatus, &a.ExtractError, &a.ExtractedAt, &a.storageKey)
+	return a, err
+}
+
+func registerJudgmentAttachmentRoutes(api, auth *gin.RouterGroup, pool *pgxpool.Pool, store storage.Store) {
+	api.GET("/judgments/:id/attachments", func(c *gin.Context) { listAttachments(c, pool) })
+	api.GET("/judgments/:id/attachments/:attachmentId", func(c *gin.Context) { downloadAttachment(c, pool, store) })
+	auth.POST("/judgments/:id/attachments", func(c *gin.Context) { uploadAttachment(c, pool, store) })
+	auth.DELETE("/judgments/:id/attachments/:attachmentId", func(c *gin.Context) { deleteAttachment(c, pool, store) })
+	registerAttachmentTextRoutes(api, auth, pool)
+}
+
+func listAttachments(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+
+	var exists bool
+	if err := pool.QueryRow(c, `SELECT EXISTS (SELECT 1 FROM judgments WHERE id=$1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil || !exists {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	rows, err := pool.Query(c, `
+SELECT `+attachmentColumns+`
+FROM judgment_attachments a
+WHERE a.judgment_id=$1
+ORDER BY a.created_at, a.id`, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	items := make([]Attachment, 0)
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		items = append(items, a)
+	}
+	c.JSON(200, items)
+}
+
+// downloadAttachment ส่งไฟล์แบบ stream รองรับ Range / If-Range / If-None-Match (ผ่าน http.ServeContent)
+// ?inline=true ให้ browser เปิดดูแทนการดาวน์โหลด
+func downloadAttachment(c *gin.Context, pool *pgxpool.Pool, store storage.Store) {
+	a, err := scanAttachment(pool.QueryRow(c, `
+SELECT `+attachmentColumns+`
+FROM judgment_attachments a
+JOIN judgments j ON j.id = a.judgment_id AND j.deleted_at IS NULL
+WHERE a.judgment_id=$1 AND a.id=$2`, c.Param("id"), c.Param("attachmentId")))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	obj, err := store.Open(c, a.storageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(404, gin.H{"error": "file is missing from storage"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer obj.Close()
+
+	disposition := "attachment"
+	if c.Query("inline") == "true" {
+		disposition = "inline"
+	}
+	c.Header("Content-Type", a.ContentType)
+	c.Header("Content-Disposition", contentDisposition(disposition, a.Filename))
+	c.Header("ETag", `"`+a.SHA256+`"`)
+	c.Header("X-Checksum-SHA256", a.SHA256)
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, a.Filename, a.CreatedAt, obj)
+}
+
+// uploadAttachment: multipart field "file" (stream ลงไฟล์ชั่วคราว ไม่อ่านทั้งไฟล์เข้าหน่วยความจำ)
+// ส่ง header X-Checksum-SHA256 มาด้วยได้ เพื่อให้ตรวจว่าไฟล์มาครบ
+func uploadAttachment(c *gin.Context, pool *pgxpool.Pool, store storage.Store) {
+	id := c.Param("id")
+	userID := c.GetString("userID")
+	if !authorizeJudgmentEdit(c, pool, id) {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, attachmentMaxBytes+1<<20)
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(400, gin.H{"error": "multipart/form-data with a file field is required"})
+		return
+	}
+	var filename string
+	var part io.Reader
+	for {
+		p, err := mr.NextPart()
+		if err != nil {
+			c.JSON(400, gin.H{"error": "file is required"})
+			return
+		}
+		if p.FormName() == "file" {
+			filename, part = attachmentFilename(p.FileName()), p
+			break
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	sum := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, sum), io.LimitReader(part, attachmentMaxBytes+1))
+	var tooBig *http.MaxBytesError
+	if errors.As(err, &tooBig) || size > attachmentMaxBytes {
+		c.JSON(413, gin.H{"error": "file too large", "max_bytes": attachmentMaxBytes})
+		return
+	}
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if size == 0 {
+		c.JSON(400, gin.H{"error": "file is empty"})
+		return
+	}
+	checksum := hex.EncodeToString(sum.Sum(nil))
+	if want := c.GetHeader("X-Checksum-SHA256"); want != "" && !strings.EqualFold(want, checksum) {
+		c.JSON(400, gin.H{"error": "checksum mismatch", "sha256": checksum})
+		return
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	mt, err := mimetype.DetectReader(tmp)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	contentType, _, _ := strings.Cut(mt.String(), ";")
+	if !containsString(attachmentTypes, contentType) {
+		c.JSON(415, gin.H{"error": "file type not allowed: " + contentType, "allowed": attachmentTypes})
+		return
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	var attID string
+	if err := tx.QueryRow(c, `SELECT uuid_generate_v4()`).Scan(&attID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	key := "judgments/" + id + "/" + attID
+	_, err = tx.Exec(c, `
+INSERT INTO judgment_attachments (id, judgment_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		attID, id, filename, contentType, size, checksum, key, nullIfEmpty(userID),
+	)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	// เขียนไฟล์ก่อน commit: ถ้าเขียนไม่สำเร็จ metadata จะไม่ถูกบันทึก
+	if err := store.Put(c, key, tmp, size, contentType); err != nil {
+		c.JSON(502, gin.H{"error": "storage: " + err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		if derr := store.Delete(c, key); derr != nil {
+			log.Printf("attachment %s: cleanup after failed commit: %v", key, derr)
+		}
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	a, err := scanAttachment(pool.QueryRow(c, `SELECT `+attachmentColumns+` FROM judgment_attachments a WHERE a.id=$1`, attID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	wakeExtractor()
+	c.JSON(201, a)
+}
+
+func deleteAttachment(c *gin.Context, pool *pgxpool.Pool, store storage.Store) {
+	id := c.Param("id")
+	if !authorizeJudgmentEdit(c, pool, id) {
+		return
+	}
+
+	var key string
+	err := pool.QueryRow(c, `DELETE FROM judgment_attachments WHERE judgment_id=$1 AND id=$2 RETURNING storage_key`,
+		id, c.Param("attachmentId")).Scan(&key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	deleteStoredFiles(c, store, []string{key})
+	c.Status(204)
+}
+
+// authorizeJudgmentEdit: judgment ต้องมีอยู่ (ไม่อยู่ในถังขยะ) และ user เป็นเจ้าของหรือ admin
+func authorizeJudgmentEdit(c *gin.Context, q querier, id string) bool {
+	var createdBy *string
+	err := q.QueryRow(c, `SELECT created_by FROM judgments WHERE id=$1 AND deleted_at IS NULL`, id).Scan(&createdBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return false
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	if !canEditJudgment(c, createdBy) {
+		c.JSON(403, gin.H{"error": "only the author or an admin can modify this judgment"})
+		return false
+	}
+	return true
+}
+
+// deleteStoredFiles ลบไฟล์หลังลบ metadata แล้ว ลบไม่สำเร็จแค่ log ไว้ (ไฟล์ค้างไม่กระทบข้อมูล)
+func deleteStoredFiles(ctx context.Context, store storage.Store, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("attachment %s: delete from storage: %v", key, err)
+		}
+	}
+}
+
+// attachmentFilename เก็บแค่ชื่อไฟล์ (ตัด path และตัวอักษรควบคุมออก)
+func attachmentFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if rs := []rune(name); len(rs) > 200 {
+		name = string(rs[len(rs)-200:])
+	}
+	return name
+}
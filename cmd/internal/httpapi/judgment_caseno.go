@@ -0,0 +1,103 @@
+package httpapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/legal"
+)
+
+// caseNoLookup: GET /judgments/case-no?q=ฎ.1234/65
+// แยกเลขคดีให้ดู (ไว้ตรวจในฟอร์มก่อนบันทึก) พร้อม judgment ที่มีเลขคดีเดียวกันอยู่แล้ว
+func caseNoLookup(c *gin.Context, pool *pgxpool.Pool) {
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	raw := strings.TrimSpace(c.Query("q"))
+	if raw == "" {
+		c.JSON(400, gin.H{"error": "q is required"})
+		return
+	}
+	n, err := legal.ParseCaseNo(raw)
+	if err != nil {
+		c.JSON(422, gin.H{"error": err.Error(), "input": raw})
+		return
+	}
+
+	var args sqlArgs
+	rows, err := pool.Query(c, `
+SELECT `+judgmentRefColumns+` FROM judgments j
+WHERE `+caseNoMatchCond(n, "j.", &args)+` AND j.deleted_at IS NULL
+ORDER BY j.judgment_date DESC NULLS LAST
+LIMIT 20`, args...)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	matches := []JudgmentRef{}
+	for rows.Next() {
+		var r JudgmentRef
+		if err := rows.Scan(&r.ID, &r.DocNo, &r.Title, &r.CaseNo, &r.Court, &r.JudgmentDate); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		r.localize(cal)
+		matches = append(matches, r)
+	}
+
+	c.JSON(200, gin.H{
+		"input":   raw,
+		"parsed":  CaseNoInfo{Type: n.Type, Prefix: n.Prefix, Number: n.Number, Year: n.Year, Normalized: n.String()},
+		"matches": matches,
+	})
+}
+
+// caseNoMatchCond: เลขคดีเดียวกัน = เลข/ปีตรงกัน และประเภท/อักษรหน้าเลขไม่ขัดกัน
+// (ส่วนที่ไม่ได้ระบุถือว่าตรงกับทุกค่า เช่น "1234/65" ตรงกับ "ฎ.1234/2565")
+func caseNoMatchCond(n legal.CaseNumber, col string, args *sqlArgs) string {
+	conds := []string{
+		col + "case_year = " + args.add(n.Year),
+		col + "case_number = " + args.add(n.Number),
+	}
+	if n.Type != legal.CaseUnspecified {
+		conds = append(conds, "("+col+"case_type = "+args.add(n.Type)+" OR "+col+"case_type = 'unspecified')")
+	}
+	if n.Prefix != "" {
+		conds = append(conds, "("+col+"case_prefix = "+args.add(n.Prefix)+" OR "+col+"case_prefix = '')")
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// caseNoDuplicates คืน judgment อื่น (ไม่อยู่ในถังขยะ) ที่เลขคดีตรงกับ judgment นี้
+// (ต้อง reindex แล้ว ส่วนประกอบของเลขคดีจึงจะมีค่า)
+func caseNoDuplicates(ctx context.Context, q querier, id string) ([]JudgmentRef, error) {
+	rows, err := q.Query(ctx, `
+SELECT `+judgmentRefColumns+`
+FROM judgments j, judgments self
+WHERE self.id = $1 AND j.id <> self.id AND j.deleted_at IS NULL
+  AND j.case_year = self.case_year AND j.case_number = self.case_number
+  AND (j.case_type = self.case_type OR 'unspecified' IN (j.case_type, self.case_type))
+  AND (j.case_prefix = self.case_prefix OR '' IN (j.case_prefix, self.case_prefix))
+ORDER BY j.created_at
+LIMIT 20`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []JudgmentRef{}
+	for rows.Next() {
+		var r JudgmentRef
+		if err := rows.Scan(&r.ID, &r.DocNo, &r.Title, &r.CaseNo, &r.Court, &r.JudgmentDate); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,516 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/legal"
+)
+
+var citationRelations = []string{"cites", "follows", "distinguishes", "overrules"}
+
+const (
+	graphMaxDepth = 3
+	graphMaxNodes = 500
+)
+
+// JudgmentRef คือข้อมูลย่อของ judgment ที่แสดงคู่กับ citation / graph
+type JudgmentRef struct {
+	ID           string  `json:"id"`
+	DocNo        *string `json:"doc_no"`
+	Title        string  `json:"title"`
+	CaseNo       *string `json:"case_no"`
+	Court        *string `json:"court"`
+	JudgmentDate *string `json:"judgment_date"`
+}
+
+const judgmentRefColumns = `j.id, j.doc_no, j.title, j.case_no, j.court, to_char(j.judgment_date,'YYYY-MM-DD')`
+
+type Citation struct {
+	ID        int64       `json:"id"`
+	CitingID  string      `json:"citing_id"`
+	CitedID   string      `json:"cited_id"`
+	Relation  string      `json:"relation"`
+	Pinpoint  *string     `json:"pinpoint"`
+	Source    string      `json:"source"` // manual / auto
+	CreatedBy *string     `json:"created_by"`
+	CreatedAt time.Time   `json:"created_at"`
+	Judgment  JudgmentRef `json:"judgment"` // judgment อีกฝั่งของการอ้างอิง
+}
+
+type citationPayload struct {
+	CitedID  string  `json:"cited_id"`
+	CaseNo   string  `json:"case_no"` // ใช้แทน cited_id ได้
+	Relation string  `json:"relation"`
+	Pinpoint *string `json:"pinpoint"` // เช่น หน้า/ย่อหน้าที่อ้าง
+}
+
+func registerJudgmentCitationRoutes(api, auth *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.GET("/judgments/:id/citations", func(c *gin.Context) { listCitations(c, pool) })
+	api.GET("/judgments/:id/graph", func(c *gin.Context) { citationGraph(c, pool) })
+	auth.POST("/judgments/:id/citations", func(c *gin.Context) { addCitation(c, pool) })
+	auth.DELETE("/judgments/:id/citations/:citationId", func(c *gin.Context) { removeCitation(c, pool) })
+}
+
+// listCitations: {cites: ที่ judgment นี้อ้างถึง, cited_by: ที่อ้างถึง judgment นี้}
+func listCitations(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	if !judgmentExists(c, pool, id) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	cites, err := loadCitations(c, pool, "ci.citing_id", "ci.cited_id", id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	citedBy, err := loadCitations(c, pool, "ci.cited_id", "ci.citing_id", id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	for _, list := range [][]Citation{cites, citedBy} {
+		for i := range list {
+			list[i].Judgment.localize(cal)
+		}
+	}
+	c.JSON(200, gin.H{"cites": cites, "cited_by": citedBy})
+}
+
+// loadCitations: self คือ column ที่เป็น judgment นี้, other คือฝั่งที่จะแสดง
+func loadCitations(ctx context.Context, pool *pgxpool.Pool, self, other, id string) ([]Citation, error) {
+	rows, err := pool.Query(ctx, `
+SELECT ci.id, ci.citing_id, ci.cited_id, ci.relation, ci.pinpoint, ci.source, ci.created_by, ci.created_at,
+       `+judgmentRefColumns+`
+FROM judgment_citations ci
+JOIN judgments j ON j.id = `+other+` AND j.deleted_at IS NULL
+WHERE `+self+` = $1 AND ci.source <> 'rejected'
+ORDER BY j.judgment_date DESC NULLS LAST, ci.id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Citation, 0)
+	for rows.Next() {
+		var ci Citation
+		r := &ci.Judgment
+		if err := rows.Scan(&ci.ID, &ci.CitingID, &ci.CitedID, &ci.Relation, &ci.Pinpoint, &ci.Source, &ci.CreatedBy, &ci.CreatedAt,
+			&r.ID, &r.DocNo, &r.Title, &r.CaseNo, &r.Court, &r.JudgmentDate); err != nil {
+			return nil, err
+		}
+		out = append(out, ci)
+	}
+	return out, rows.Err()
+}
+
+func addCitation(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	userID := c.GetString("userID")
+
+	var in citationPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if in.Relation == "" {
+		in.Relation = "cites"
+	}
+	if !containsString(citationRelations, in.Relation) {
+		c.JSON(400, gin.H{"error": "relation must be one of " + strings.Join(citationRelations, ", ")})
+		return
+	}
+	if !authorizeJudgmentEdit(c, pool, id) {
+		return
+	}
+
+	citedID := in.CitedID
+	if citedID == "" {
+		if strings.TrimSpace(in.CaseNo) == "" {
+			c.JSON(400, gin.H{"error": "cited_id or case_no is required"})
+			return
+		}
+		matches, err := judgmentsByCaseNo(c, pool, in.CaseNo)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		switch len(matches) {
+		case 0:
+			c.JSON(404, gin.H{"error": "no judgment with case_no " + in.CaseNo})
+			return
+		case 1:
+			citedID = matches[0].ID
+		default:
+			cal, _ := requestCalendar(c)
+			localizeRefs(matches, cal)
+			c.JSON(409, gin.H{"error": "case_no matches more than one judgment; use cited_id", "candidates": matches})
+			return
+		}
+	}
+	if citedID == id {
+		c.JSON(400, gin.H{"error": "a judgment cannot cite itself"})
+		return
+	}
+	if !uuidRe.MatchString(citedID) || !judgmentExists(c, pool, citedID) {
+		c.JSON(404, gin.H{"error": "cited judgment not found"})
+		return
+	}
+
+	// ถ้ามีอยู่แล้ว (รวม auto) ให้กลายเป็น manual ตามที่ผู้ใช้ระบุ
+	var citationID int64
+	err := pool.QueryRow(c, `
+INSERT INTO judgment_citations (citing_id, cited_id, relation, pinpoint, source, created_by)
+VALUES ($1, $2, $3, $4, 'manual', $5)
+ON CONFLICT (citing_id, cited_id) DO UPDATE
+SET relation=EXCLUDED.relation, pinpoint=EXCLUDED.pinpoint, source='manual', created_by=EXCLUDED.created_by
+RETURNING id`, id, citedID, in.Relation, in.Pinpoint, nullIfEmpty(userID)).Scan(&citationID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	cites, err := loadCitations(c, pool, "ci.citing_id", "ci.cited_id", id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	for _, ci := range cites {
+		if ci.ID == citationID {
+			cal, _ := requestCalendar(c)
+			ci.Judgment.localize(cal)
+			c.JSON(201, ci)
+			return
+		}
+	}
+	c.JSON(201, gin.H{"id": citationID})
+}
+
+// removeCitation: citation ที่ตรวจพบอัตโนมัติจะถูกทำเครื่องหมาย rejected แทนการลบ
+// เพื่อไม่ให้กลับมาอีกเมื่อบันทึก judgment ครั้งถัดไป
+func removeCitation(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	if !authorizeJudgmentEdit(c, pool, id) {
+		return
+	}
+	citationID, err := strconv.ParseInt(c.Param("citationId"), 10, 64)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	var source string
+	err = pool.QueryRow(c, `SELECT source FROM judgment_citations WHERE id=$1 AND citing_id=$2 AND source <> 'rejected'`,
+		citationID, id).Scan(&source)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	q := `DELETE FROM judgment_citations WHERE id=$1`
+	if source == "auto" {
+		q = `UPDATE judgment_citations SET source='rejected' WHERE id=$1`
+	}
+	if _, err := pool.Exec(c, q, citationID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(204)
+}
+
+type GraphNode struct {
+	JudgmentRef
+	Depth int `json:"depth"` // ระยะจาก judgment ตั้งต้น
+}
+
+type GraphEdge struct {
+	From     string  `json:"from"` // citing
+	To       string  `json:"to"`   // cited
+	Relation string  `json:"relation"`
+	Pinpoint *string `json:"pinpoint,omitempty"`
+}
+
+// citationGraph: GET /judgments/:id/graph?depth=N&direction=both|out|in
+// ไล่ตาม citation ทีละชั้นจาก judgment ตั้งต้น (สูงสุด graphMaxDepth ชั้น / graphMaxNodes โหนด)
+func citationGraph(c *gin.Context, pool *pgxpool.Pool) {
+	root := c.Param("id")
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "1"))
+	if err != nil || depth < 1 || depth > graphMaxDepth {
+		c.JSON(400, gin.H{"error": "depth must be between 1 and " + strconv.Itoa(graphMaxDepth)})
+		return
+	}
+	var cond string
+	switch c.DefaultQuery("direction", "both") {
+	case "out":
+		cond = "citing_id = ANY($1::uuid[])"
+	case "in":
+		cond = "cited_id = ANY($1::uuid[])"
+	case "both":
+		cond = "(citing_id = ANY($1::uuid[]) OR cited_id = ANY($1::uuid[]))"
+	default:
+		c.JSON(400, gin.H{"error": "direction must be both, out or in"})
+		return
+	}
+	if !judgmentExists(c, pool, root) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	depthOf := map[string]int{root: 0}
+	order := []string{root}
+	edges := []GraphEdge{}
+	seenEdge := map[int64]bool{}
+	truncated := false
+	frontier := []string{root}
+
+	for d := 1; d <= depth && len(frontier) > 0 && !truncated; d++ {
+		rows, err := pool.Query(c, `
+SELECT id, citing_id, cited_id, relation, pinpoint
+FROM judgment_citations
+WHERE source <> 'rejected' AND `+cond+`
+ORDER BY id`, frontier)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		next := []string{}
+		for rows.Next() {
+			var edgeID int64
+			var e GraphEdge
+			if err := rows.Scan(&edgeID, &e.From, &e.To, &e.Relation, &e.Pinpoint); err != nil {
+				rows.Close()
+				c.JSON(500, gin.H{"error": err.Error()})
+				return
+			}
+			if seenEdge[edgeID] {
+				continue
+			}
+			for _, n := range []string{e.From, e.To} {
+				if _, ok := depthOf[n]; ok {
+					continue
+				}
+				if len(depthOf) >= graphMaxNodes {
+					truncated = true
+					break
+				}
+				depthOf[n] = d
+				order = append(order, n)
+				next = append(next, n)
+			}
+			_, okFrom := depthOf[e.From]
+			_, okTo := depthOf[e.To]
+			if okFrom && okTo {
+				seenEdge[edgeID] = true
+				edges = append(edges, e)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		frontier = next
+	}
+
+	// ข้อมูลโหนด (ตัด judgment ที่อยู่ในถังขยะออก พร้อมเส้นที่ต่อกับมัน)
+	refs, err := loadJudgmentRefs(c, pool, order)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	nodes := make([]GraphNode, 0, len(order))
+	for _, id := range order {
+		if r, ok := refs[id]; ok {
+			r.localize(cal)
+			nodes = append(nodes, GraphNode{JudgmentRef: r, Depth: depthOf[id]})
+		}
+	}
+	visible := edges[:0]
+	for _, e := range edges {
+		if _, ok := refs[e.From]; !ok {
+			continue
+		}
+		if _, ok := refs[e.To]; !ok {
+			continue
+		}
+		visible = append(visible, e)
+	}
+
+	c.JSON(200, gin.H{
+		"root":      root,
+		"depth":     depth,
+		"nodes":     nodes,
+		"edges":     visible,
+		"truncated": truncated,
+	})
+}
+
+func loadJudgmentRefs(ctx context.Context, pool *pgxpool.Pool, ids []string) (map[string]JudgmentRef, error) {
+	rows, err := pool.Query(ctx, `SELECT `+judgmentRefColumns+` FROM judgments j WHERE j.id = ANY($1::uuid[]) AND j.deleted_at IS NULL`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[string]JudgmentRef{}
+	for rows.Next() {
+		var r JudgmentRef
+		if err := rows.Scan(&r.ID, &r.DocNo, &r.Title, &r.CaseNo, &r.Court, &r.JudgmentDate); err != nil {
+			return nil, err
+		}
+		out[r.ID] = r
+	}
+	return out, rows.Err()
+}
+
+func judgmentsByCaseNo(ctx context.Context, pool *pgxpool.Pool, caseNo string) ([]JudgmentRef, error) {
+	refs := caseRefs(&caseNo)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	rows, err := pool.Query(ctx, `
+SELECT `+judgmentRefColumns+` FROM judgments j
+WHERE j.case_year = $1 AND j.case_number = $2 AND j.deleted_at IS NULL
+ORDER BY j.judgment_date DESC NULLS LAST
+LIMIT 20`, refs[0].Year, refs[0].Number)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []JudgmentRef{}
+	for rows.Next() {
+		var r JudgmentRef
+		if err := rows.Scan(&r.ID, &r.DocNo, &r.Title, &r.CaseNo, &r.Court, &r.JudgmentDate); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// caseRefs คืนเลข/ปีของ case_no (แยกด้วย ParseCaseNo ก่อน ถ้าไม่ได้ค่อยหาเลขคดีในข้อความ)
+func caseRefs(caseNo *string) []legal.CaseRef {
+	if n, err := legal.ParseCaseNo(deref(caseNo)); err == nil {
+		return []legal.CaseRef{n.Ref()}
+	}
+	return legal.FindCaseRefs(deref(caseNo))
+}
+
+func judgmentExists(ctx context.Context, q querier, id string) bool {
+	var ok bool
+	err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM judgments WHERE id=$1 AND deleted_at IS NULL)`, id).Scan(&ok)
+	return err == nil && ok
+}
+
+// ---- ตรวจหา citation อัตโนมัติ (เรียกจาก reindexJudgment) ----
+
+// syncAutoCitations ตั้ง citation แบบ auto ของ judgment ให้ตรงกับเลขคดีที่พบในเนื้อหา
+// ไม่แตะ citation ที่ผู้ใช้เพิ่มเอง (manual) หรือลบทิ้งไปแล้ว (rejected)
+func syncAutoCitations(ctx context.Context, q querier, id string, caseNo *string, texts ...string) error {
+	seen := map[legal.CaseRef]bool{}
+	for _, r := range caseRefs(caseNo) {
+		seen[r] = true // ไม่นับเลขคดีของตัวเอง
+	}
+	numbers, years, refs := []int{}, []int{}, []string{}
+	for _, t := range texts {
+		for _, r := range legal.FindCaseRefs(t) {
+			if !seen[r] {
+				seen[r] = true
+				numbers = append(numbers, r.Number)
+				years = append(years, r.Year)
+				refs = append(refs, r.String())
+			}
+		}
+	}
+
+	// เก็บเลขคดีที่อ้างไว้ทั้งหมด (รวมคดีที่ยังไม่มีในระบบ) ให้ linkCitingJudgments หาเจอเมื่อคดีนั้นถูกเพิ่ม
+	if _, err := q.Exec(ctx, `UPDATE judgments SET cited_refs = $2 WHERE id = $1`, id, refs); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, `
+DELETE FROM judgment_citations ci
+WHERE ci.citing_id = $1 AND ci.source = 'auto'
+  AND NOT EXISTS (
+    SELECT 1 FROM judgments j
+    JOIN unnest($2::int[], $3::int[]) AS r(num, yr) ON j.case_number = r.num AND j.case_year = r.yr
+    WHERE j.id = ci.cited_id)`, id, numbers, years)
+	if err != nil || len(numbers) == 0 {
+		return err
+	}
+	_, err = q.Exec(ctx, `
+INSERT INTO judgment_citations (citing_id, cited_id, source)
+SELECT $1::uuid, j.id, 'auto'
+FROM judgments j
+JOIN unnest($2::int[], $3::int[]) AS r(num, yr) ON j.case_number = r.num AND j.case_year = r.yr
+WHERE j.id <> $1::uuid AND j.deleted_at IS NULL
+ON CONFLICT (citing_id, cited_id) DO NOTHING`, id, numbers, years)
+	return err
+}
+
+// linkCitingJudgments หา judgment อื่นที่อ้างเลขคดีของ judgment นี้ไว้ก่อนแล้ว (เช่น เพิ่งนำเข้าคดีที่ถูกอ้าง)
+// จาก cited_refs ที่ syncAutoCitations เก็บไว้ แล้วตรวจ citation ของ judgment เหล่านั้นใหม่
+func linkCitingJudgments(ctx context.Context, q querier, id string, caseNo *string) error {
+	refs := []string{}
+	for _, r := range caseRefs(caseNo) {
+		refs = append(refs, r.String())
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		id                            string
+		caseNo                        *string
+		title                         string
+		facts, issues, holding, notes *string
+	}
+	rows, err := q.Query(ctx, `
+SELECT id, case_no, title, facts, issues, holding, notes
+FROM judgments
+WHERE cited_refs && $1::text[] AND id <> $2 AND deleted_at IS NULL`, refs, id)
+	if err != nil {
+		return err
+	}
+	cands := []candidate{}
+	for rows.Next() {
+		var cd candidate
+		if err := rows.Scan(&cd.id, &cd.caseNo, &cd.title, &cd.facts, &cd.issues, &cd.holding, &cd.notes); err != nil {
+			rows.Close()
+			return err
+		}
+		cands = append(cands, cd)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, cd := range cands {
+		if err := syncAutoCitations(ctx, q, cd.id, cd.caseNo,
+			cd.title, deref(cd.facts), deref(cd.issues), deref(cd.holding), deref(cd.notes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
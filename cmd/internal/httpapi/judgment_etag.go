@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+// REQUIRE_IF_MATCH=true: PUT/PATCH/DELETE ต้องส่ง If-Match มาเสมอ (ไม่งั้นตอบ 428)
+var requireIfMatch = strings.EqualFold(getEnv("REQUIRE_IF_MATCH", "false"), "true")
+
+// judgmentETag: ระบบปีเปลี่ยนหน้าตาของ judgment_date จึงต้องอยู่ใน ETag ด้วย เช่น "v12-be"
+// (ไม่งั้น cache ที่ได้มาตอน ?calendar=ce จะตอบ 304 ให้ request ที่ขอ be)
+func judgmentETag(version int, cal thai.Calendar) string {
+	return `"v` + itoa(version) + "-" + cal.String() + `"`
+}
+
+// judgmentVersionMatches ใช้กับ If-Match: เทียบแค่ version ไม่สนระบบปี
+// (GET ด้วย be แล้วแก้ด้วย ce ได้) รับ ETag แบบเก่า "v12" ด้วย
+func judgmentVersionMatches(header string, version int) bool {
+	return etagMatchesStrong(header, judgmentETag(version, thai.Gregorian)) ||
+		etagMatchesStrong(header, judgmentETag(version, thai.Buddhist)) ||
+		etagMatchesStrong(header, `"v`+itoa(version)+`"`)
+}
+
+// etagMatches เทียบ ETag กับ header If-None-Match (หลายค่าคั่น comma หรือ *)
+// เทียบแบบ weak คือไม่สน prefix W/
+func etagMatches(header, etag string) bool {
+	return matchETagList(header, etag, true)
+}
+
+// etagMatchesStrong เทียบแบบ strong สำหรับ If-Match (RFC 7232 §3.1): ค่าที่มี W/ ไม่ตรงกับอะไรเลย
+func etagMatchesStrong(header, etag string) bool {
+	return matchETagList(header, etag, false)
+}
+
+func matchETagList(header, etag string, weak bool) bool {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if weak {
+			part = strings.TrimPrefix(part, "W/")
+		}
+		if part == "*" || part == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch เช็ค If-Match กับ version ปัจจุบัน; ถ้าไม่ผ่านจะตอบ 412 (หรือ 428) ให้แล้วคืน false
+// current คือข้อมูลล่าสุดที่ส่งกลับไปให้ client ใช้ merge
+func checkIfMatch(c *gin.Context, version int, current func() (Judgment, error)) bool {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	cal, _ := requestCalendar(c)
+	etag := judgmentETag(version, cal)
+	if header == "" {
+		if requireIfMatch {
+			c.JSON(428, gin.H{"error": "If-Match header required", "etag": etag, "version": version})
+			return false
+		}
+		return true
+	}
+	if judgmentVersionMatches(header, version) {
+		return true
+	}
+
+	body := gin.H{"error": "precondition failed: judgment was modified", "etag": etag, "version": version}
+	if j, err := current(); err == nil {
+		j.localize(cal)
+		body["current"] = j
+	}
+	c.Header("ETag", etag)
+	c.Header("Vary", "X-Calendar")
+	c.JSON(412, body)
+	return false
+}
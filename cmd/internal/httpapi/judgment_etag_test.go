@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"testing"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+func TestJudgmentETag(t *testing.T) {
+	if got := judgmentETag(12, thai.Buddhist); got != `"v12-be"` {
+		t.Errorf("judgmentETag(be) = %s", got)
+	}
+	if got := judgmentETag(12, thai.Gregorian); got != `"v12-ce"` {
+		t.Errorf("judgmentETag(ce) = %s", got)
+	}
+	// If-None-Match ต้องไม่ข้ามระบบปี
+	if etagMatches(`"v12-ce"`, judgmentETag(12, thai.Buddhist)) {
+		t.Error("ce etag must not match be representation")
+	}
+	// If-None-Match เทียบแบบ weak
+	if !etagMatches(`W/"v12-be"`, judgmentETag(12, thai.Buddhist)) {
+		t.Error("If-None-Match must ignore W/")
+	}
+}
+
+func TestJudgmentVersionMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"v12-be"`, true},
+		{`"v12-ce"`, true},
+		// If-Match เทียบแบบ strong: W/ ไม่ผ่าน
+		{`W/"v12-be"`, false},
+		{`W/"v12"`, false},
+		{`W/"v12-be", "v12-ce"`, true},
+		{`"v12"`, true},
+		{`"v11-be", "v12-ce"`, true},
+		{`*`, true},
+		{`"v11-be"`, false},
+		{`"v120-be"`, false},
+		{`"v1"`, false},
+	}
+	for _, tt := range tests {
+		if got := judgmentVersionMatches(tt.header, 12); got != tt.want {
+			t.Errorf("judgmentVersionMatches(%s, 12) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package httpapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/office"
+	"judgment-notes/cmd/internal/thai"
+)
+
+// judgmentExporter เขียน judgment ทีละรายการลง response (stream)
+type judgmentExporter interface {
+	Write(j Judgment) error
+	Close() error
+}
+
+type exportFormat struct {
+	ContentType string
+	Ext         string
+	New         func(w io.Writer) (judgmentExporter, error)
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":    {"text/csv; charset=utf-8", "csv", newCSVExporter},
+	"ndjson": {"application/x-ndjson", "ndjson", newNDJSONExporter},
+	"md":     {"text/markdown; charset=utf-8", "md", newMarkdownExporter},
+	"docx":   {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "docx", newDocxExporter},
+	"pdf":    {"application/pdf", "pdf", newPDFExporter},
+}
+
+func registerJudgmentExportRoutes(api *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.GET("/judgments/export", func(c *gin.Context) { exportJudgments(c, pool) })
+	api.GET("/judgments/:id/export", func(c *gin.Context) { exportJudgment(c, pool) })
+}
+
+func exportFormatParam(c *gin.Context) (exportFormat, bool) {
+	name := strings.ToLower(c.DefaultQuery("format", "csv"))
+	ef, ok := exportFormats[name]
+	if !ok {
+		c.JSON(400, gin.H{"error": "format must be one of csv, ndjson, md, docx, pdf"})
+	}
+	return ef, ok
+}
+
+// exportJudgments: GET /api/judgments/export?format=  (ใช้ filter/sort เดียวกับ listJudgments แต่ไม่แบ่งหน้า)
+func exportJudgments(c *gin.Context, pool *pgxpool.Pool) {
+	ef, ok := exportFormatParam(c)
+	if !ok {
+		return
+	}
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	f, err := parseJudgmentFilter(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	jq := f.build()
+	sort, err := parseJudgmentSort(c, jq)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	q := `
+SELECT ` + judgmentColumns + `
+FROM judgments
+WHERE ` + jq.Where + `
+ORDER BY ` + sort.orderBy()
+
+	rows, err := pool.Query(c, q, jq.Args...)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	startExport(c, ef, "judgments-"+time.Now().Format("20060102"))
+	ex, err := ef.New(c.Writer)
+	if err == nil {
+		for rows.Next() {
+			var j Judgment
+			if j, err = scanJudgment(rows); err != nil {
+				break
+			}
+			j.localize(cal)
+			if err = ex.Write(j); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = rows.Err()
+		}
+		if err == nil {
+			err = ex.Close()
+		}
+	}
+	if err != nil {
+		exportFailed(c, err)
+	}
+}
+
+// exportJudgment: GET /api/judgments/:id/export?format=
+func exportJudgment(c *gin.Context, pool *pgxpool.Pool) {
+	ef, ok := exportFormatParam(c)
+	if !ok {
+		return
+	}
+	exportJudgmentAs(c, pool, c.Param("id"), ef)
+}
+
+func exportJudgmentAs(c *gin.Context, pool *pgxpool.Pool, id string, ef exportFormat) {
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+
+	q := `
+SELECT ` + judgmentColumns + `
+FROM judgments
+WHERE id=$1 AND deleted_at IS NULL`
+
+	j, err := scanJudgment(pool.QueryRow(c, q, id))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	j.localize(cal)
+
+	name := j.ID
+	if j.DocNo != nil && *j.DocNo != "" {
+		name = *j.DocNo
+	}
+	startExport(c, ef, "judgment-"+name)
+	ex, err := ef.New(c.Writer)
+	if err == nil {
+		if err = ex.Write(j); err == nil {
+			err = ex.Close()
+		}
+	}
+	if err != nil {
+		exportFailed(c, err)
+	}
+}
+
+var unsafeFilename = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+func startExport(c *gin.Context, ef exportFormat, name string) {
+	name = unsafeFilename.ReplaceAllString(name, "_") + "." + ef.Ext
+	c.Header("Content-Type", ef.ContentType)
+	c.Header("Content-Disposition", contentDisposition("attachment", name))
+}
+
+// contentDisposition ใส่ทั้ง filename (ASCII) และ filename* (UTF-8) ให้ชื่อไฟล์ภาษาไทยถูกต้อง
+func contentDisposition(kind, name string) string {
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, kind, asciiFilename(name), url.PathEscape(name))
+}
+
+// exportFailed: ถ้ายังไม่ได้เขียน body ตอบ error เป็น JSON ได้
+// ถ้าเขียนไปแล้วเปลี่ยน status ไม่ได้ ทำได้แค่ log ไว้ (client จะได้ไฟล์ไม่ครบ)
+func exportFailed(c *gin.Context, err error) {
+	if c.Writer.Written() {
+		log.Printf("export judgments: %v", err)
+		return
+	}
+	c.Writer.Header().Del("Content-Disposition")
+	status := 500
+	if errors.Is(err, errExportTooLarge) {
+		status = 413
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// asciiFilename สำหรับ client เก่าที่ไม่รองรับ filename* (ตัวอักษรไทยกลายเป็น _)
+func asciiFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0x7e || r < 0x20 || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// judgmentSection คือหัวข้อเนื้อหาที่ใช้ร่วมกันใน export แบบเอกสาร (md, docx)
+type judgmentSection struct {
+	Label string
+	Text  string
+}
+
+func judgmentMeta(j Judgment) []judgmentSection {
+	meta := []judgmentSection{
+		{"เลขที่เอกสาร", deref(j.DocNo)},
+		{"คดีหมายเลข", deref(j.CaseNo)},
+		{"ศาล", deref(j.Court)},
+		{"หมวด", deref(j.Category)},
+		{"วันที่พิพากษา", judgmentDateText(j)},
+		{"คู่ความ", deref(j.Parties)},
+		{"แท็ก", strings.Join(j.Tags, ", ")},
+	}
+	return nonEmptySections(meta)
+}
+
+func judgmentBody(j Judgment) []judgmentSection {
+	return nonEmptySections([]judgmentSection{
+		{"ข้อเท็จจริง", deref(j.Facts)},
+		{"ประเด็น", deref(j.Issues)},
+		{"คำวินิจฉัย", deref(j.Holding)},
+		{"หมายเหตุ", deref(j.Notes)},
+	})
+}
+
+// judgmentDateText แสดงทั้งพุทธศักราชและคริสต์ศักราช เช่น "15 มีนาคม พ.ศ. 2567 (2024-03-15)"
+func judgmentDateText(j Judgment) string {
+	if j.JudgmentDate == nil {
+		return ""
+	}
+	// JudgmentDate อาจเป็น พ.ศ. แล้ว (?calendar=be)
+	d, err := thai.ParseDate(*j.JudgmentDate, j.calendar)
+	if err != nil {
+		return *j.JudgmentDate
+	}
+	return thai.FormatDateBE(d) + " (" + d.Format("2006-01-02") + ")"
+}
+
+func nonEmptySections(in []judgmentSection) []judgmentSection {
+	out := in[:0]
+	for _, s := range in {
+		if strings.TrimSpace(s.Text) != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ---- csv ----
+
+var csvHeader = []string{
+	"id", "doc_no", "title", "case_no", "court", "category", "judgment_date", "parties",
+	"facts", "issues", "holding", "notes", "tags",
+	"created_at", "updated_at", "created_by_name", "updated_by_name", "version",
+}
+
+type csvExporter struct{ w *csv.Writer }
+
+func newCSVExporter(w io.Writer) (judgmentExporter, error) {
+	// BOM ให้ Excel อ่านภาษาไทยเป็น UTF-8
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	return &csvExporter{cw}, cw.Write(csvHeader)
+}
+
+func (e *csvExporter) Write(j Judgment) error {
+	return e.w.Write([]string{
+		j.ID, deref(j.DocNo), j.Title, deref(j.CaseNo), deref(j.Court), deref(j.Category), deref(j.JudgmentDate), deref(j.Parties),
+		deref(j.Facts), deref(j.Issues), deref(j.Holding), deref(j.Notes), strings.Join(j.Tags, "; "),
+		j.CreatedAt.Format(time.RFC3339), j.UpdatedAt.Format(time.RFC3339),
+		deref(j.CreatedByName), deref(j.UpdatedByName), strconv.Itoa(j.Version),
+	})
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ---- ndjson ----
+
+type ndjsonExporter struct{ enc *json.Encoder }
+
+func newNDJSONExporter(w io.Writer) (judgmentExporter, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &ndjsonExporter{enc}, nil
+}
+
+func (e *ndjsonExporter) Write(j Judgment) error { return e.enc.Encode(j) }
+func (e *ndjsonExporter) Close() error           { return nil }
+
+// ---- markdown ----
+
+type markdownExporter struct {
+	w     io.Writer
+	count int
+}
+
+func newMarkdownExporter(w io.Writer) (judgmentExporter, error) {
+	return &markdownExporter{w: w}, nil
+}
+
+func (e *markdownExporter) Write(j Judgment) error {
+	var b strings.Builder
+	if e.count > 0 {
+		b.WriteString("\n---\n\n")
+	}
+	e.count++
+
+	b.WriteString("# " + oneLine(j.Title) + "\n\n")
+	for _, m := range judgmentMeta(j) {
+		b.WriteString("- **" + m.Label + ":** " + oneLine(m.Text) + "\n")
+	}
+	for _, s := range judgmentBody(j) {
+		b.WriteString("\n## " + s.Label + "\n\n" + strings.TrimSpace(s.Text) + "\n")
+	}
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *markdownExporter) Close() error { return nil }
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ---- docx ----
+
+type docxExporter struct {
+	d     *office.Docx
+	count int
+}
+
+func newDocxExporter(w io.Writer) (judgmentExporter, error) {
+	d, err := office.NewDocx(w)
+	if err != nil {
+		return nil, err
+	}
+	return &docxExporter{d: d}, nil
+}
+
+func (e *docxExporter) Write(j Judgment) error {
+	// หนึ่งเรื่องต่อหน้า
+	if e.count > 0 {
+		e.d.PageBreak()
+	}
+	e.count++
+
+	e.d.Heading(1, j.Title)
+	for _, m := range judgmentMeta(j) {
+		e.d.Field(m.Label, oneLine(m.Text))
+	}
+	for _, s := range judgmentBody(j) {
+		e.d.Heading(2, s.Label)
+		e.d.Paragraph(strings.TrimSpace(s.Text))
+	}
+	return e.d.Paragraph("")
+}
+
+func (e *docxExporter) Close() error { return e.d.Close() }
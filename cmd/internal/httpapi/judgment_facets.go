@@ -0,0 +1,84 @@
+package httpapi
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// JudgmentFacets นับจำนวนตาม court / tag / ปี ของผลลัพธ์ที่ผ่าน filter แล้ว (ไว้ทำ sidebar)
+type JudgmentFacets struct {
+	Court []FacetCount `json:"court"`
+	Tag   []FacetCount `json:"tag"`
+	Year  []FacetCount `json:"year"`
+}
+
+const maxFacetValues = 50
+
+func loadJudgmentFacets(ctx context.Context, pool *pgxpool.Pool, q judgmentQuery) (*JudgmentFacets, error) {
+	var f JudgmentFacets
+	var err error
+
+	if f.Court, err = facetCounts(ctx, pool, `
+SELECT court, COUNT(*) FROM judgments
+WHERE `+q.Where+` AND NULLIF(btrim(court), '') IS NOT NULL
+GROUP BY court ORDER BY COUNT(*) DESC, court
+LIMIT `+itoa(maxFacetValues), q.Args); err != nil {
+		return nil, err
+	}
+
+	if f.Tag, err = facetCounts(ctx, pool, `
+SELECT t.tag, COUNT(*) FROM judgments, unnest(tags) AS t(tag)
+WHERE `+q.Where+`
+GROUP BY t.tag ORDER BY COUNT(*) DESC, t.tag
+LIMIT `+itoa(maxFacetValues), q.Args); err != nil {
+		return nil, err
+	}
+
+	if f.Year, err = facetCounts(ctx, pool, `
+SELECT EXTRACT(YEAR FROM judgment_date)::int::text, COUNT(*) FROM judgments
+WHERE `+q.Where+` AND judgment_date IS NOT NULL
+GROUP BY 1 ORDER BY 1 DESC`, q.Args); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// localize แปลงปีใน facet เป็นระบบปีที่ขอ
+func (f *JudgmentFacets) localize(cal thai.Calendar) {
+	if cal != thai.Buddhist {
+		return
+	}
+	for i, y := range f.Year {
+		if n, err := strconv.Atoi(y.Value); err == nil {
+			f.Year[i].Value = strconv.Itoa(n + thai.BEOffset)
+		}
+	}
+}
+
+func facetCounts(ctx context.Context, pool *pgxpool.Pool, sql string, args []any) ([]FacetCount, error) {
+	rows, err := pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]FacetCount, 0)
+	for rows.Next() {
+		var fc FacetCount
+		if err := rows.Scan(&fc.Value, &fc.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, fc)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,236 @@
+package httpapi
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"judgment-notes/cmd/internal/legal"
+	"judgment-notes/cmd/internal/search"
+)
+
+// judgmentFilter คือเงื่อนไขค้นหา/กรองของ GET /api/judgments (ใช้ร่วมกับ endpoint อื่นที่รับ filter ชุดเดียวกัน)
+type judgmentFilter struct {
+	Search       string
+	Courts       []string
+	Categories   []string
+	Tags         []string
+	TagsAll      bool // tags_mode=all: ต้องมีครบทุก tag
+	DateFrom     string
+	DateTo       string
+	CaseNoPrefix string
+	Author       string
+	Has          []string
+	Statutes     []legal.SectionRef // statute=civil:420 (Section "" = ทุกมาตราของประมวล)
+}
+
+// field ที่ใช้กับ ?has= ได้ และเงื่อนไขว่า "มีค่า"
+var hasFieldConds = map[string]string{
+	"case_no":       "NULLIF(btrim(case_no), '') IS NOT NULL",
+	"court":         "NULLIF(btrim(court), '') IS NOT NULL",
+	"category":      "category IS NOT NULL",
+	"judgment_date": "judgment_date IS NOT NULL",
+	"parties":       "NULLIF(btrim(parties), '') IS NOT NULL",
+	"facts":         "NULLIF(btrim(facts), '') IS NOT NULL",
+	"issues":        "NULLIF(btrim(issues), '') IS NOT NULL",
+	"holding":       "NULLIF(btrim(holding), '') IS NOT NULL",
+	"notes":         "NULLIF(btrim(notes), '') IS NOT NULL",
+	"tags":          "cardinality(tags) > 0",
+	"case_no_error": "case_no_error IS NOT NULL", // case_no ที่แยกเป็นเลขคดีไม่ได้ (ไว้ตามแก้ข้อมูล)
+}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// parseJudgmentFilter อ่าน filter จาก query string
+// ค่าหลายค่าส่งได้ทั้งแบบซ้ำ (?tags=a&tags=b) หรือคั่นด้วย comma (?tags=a,b)
+func parseJudgmentFilter(c *gin.Context) (judgmentFilter, error) {
+	f := judgmentFilter{
+		Search:       strings.TrimSpace(c.Query("search")),
+		Courts:       queryList(c, "court"),
+		Categories:   queryList(c, "category"),
+		Tags:         queryList(c, "tags"),
+		CaseNoPrefix: strings.TrimSpace(c.Query("case_no")),
+		Author:       strings.TrimSpace(c.Query("author")),
+		Has:          queryList(c, "has"),
+	}
+
+	switch strings.ToLower(c.DefaultQuery("tags_mode", "any")) {
+	case "any":
+	case "all":
+		f.TagsAll = true
+	default:
+		return f, errors.New("tags_mode must be any or all")
+	}
+
+	var err error
+	if f.DateFrom, err = dateParam(c, "date_from"); err != nil {
+		return f, err
+	}
+	if f.DateTo, err = dateParam(c, "date_to"); err != nil {
+		return f, err
+	}
+
+	if f.Author != "" && !uuidRe.MatchString(f.Author) {
+		return f, errors.New("author must be a user id")
+	}
+	for _, h := range f.Has {
+		if _, ok := hasFieldConds[h]; !ok {
+			return f, errors.New("unknown field in has: " + h)
+		}
+	}
+	for _, v := range queryList(c, "statute") {
+		ref, err := parseSectionRef(v)
+		if err != nil {
+			return f, err
+		}
+		f.Statutes = append(f.Statutes, ref)
+	}
+	return f, nil
+}
+
+// parseSectionRef อ่าน "civil:420" / "civil:188/1" / "civil"
+func parseSectionRef(v string) (legal.SectionRef, error) {
+	code, section, hasSection := strings.Cut(v, ":")
+	if _, ok := legal.StatuteByCode(code); !ok {
+		return legal.SectionRef{}, errors.New("unknown statute: " + code)
+	}
+	ref := legal.SectionRef{Code: code}
+	if hasSection {
+		if ref.Section = legal.NormalizeSection(section); ref.Section == "" {
+			return ref, errors.New("invalid statute section: " + v)
+		}
+	}
+	return ref, nil
+}
+
+func queryList(c *gin.Context, key string) []string {
+	out := []string{}
+	for _, v := range c.QueryArray(key) {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func dateParam(c *gin.Context, key string) (string, error) {
+	v := strings.TrimSpace(c.Query(key))
+	if v == "" {
+		return "", nil
+	}
+	// ปีอ่านตาม ?calendar= (2567-03-15 ต้องมี calendar=be หรือเขียน 15 มี.ค. พ.ศ. 2567) แล้วเทียบใน DB เป็น ค.ศ.
+	cal, err := requestCalendar(c)
+	if err != nil {
+		return "", err
+	}
+	iso, err := parseDateInput(v, cal)
+	if err != nil {
+		return "", errors.New(key + " must be a date such as 2024-03-15, or 2567-03-15 with calendar=be")
+	}
+	return iso, nil
+}
+
+// sqlArgs เก็บ parameter ของ query แล้วคืน placeholder ($1, $2, ...)
+type sqlArgs []any
+
+func (a *sqlArgs) add(v any) string {
+	*a = append(*a, v)
+	return "$" + itoa(len(*a))
+}
+
+// judgmentQuery คือส่วนของ SQL ที่ได้จาก filter
+type judgmentQuery struct {
+	Where    string
+	Args     sqlArgs
+	RankExpr string   // คะแนนความเกี่ยวข้อง (0 ถ้าไม่ได้ค้นหา)
+	Terms    []string // คำที่ใช้ไฮไลต์ (nil ถ้าไม่ได้ค้นหา)
+	TSQuery  string   // tsquery literal ของคำค้น (ใช้หาไฟล์แนบที่ตรง)
+}
+
+// ไฟล์แนบที่ตรงคำค้นให้คะแนนครึ่งหนึ่งของเนื้อหาใน judgment เอง
+const attachmentRankFactor = "0.5"
+
+// build แปลง filter เป็น WHERE (ซ่อนรายการที่อยู่ในถังขยะเสมอ)
+func (f judgmentFilter) build() judgmentQuery {
+	q := judgmentQuery{RankExpr: "0::float8"}
+	conds := []string{"deleted_at IS NULL"}
+
+	// full-text search (ตัดคำไทยฝั่ง Go)
+	if f.Search != "" {
+		tsq := search.Query(f.Search)
+		if tsq == "" {
+			conds = append(conds, "false") // มีแต่เครื่องหมาย ไม่มีคำให้ค้น
+		} else {
+			p := q.Args.add(tsq) + "::tsquery"
+			// ค้นทั้งเนื้อหา judgment และข้อความในไฟล์แนบ
+			attMatch := "FROM judgment_attachments a WHERE a.judgment_id = judgments.id AND a.search_vector @@ " + p
+			conds = append(conds, "(search_vector @@ "+p+" OR EXISTS (SELECT 1 "+attMatch+"))")
+			q.RankExpr = "(COALESCE(ts_rank_cd(search_vector, " + p + "), 0) + COALESCE((SELECT max(ts_rank_cd(a.search_vector, " + p + ")) " +
+				attMatch + "), 0) * " + attachmentRankFactor + ")::float8"
+			q.Terms = search.Terms(f.Search)
+			q.TSQuery = tsq
+		}
+	}
+
+	if len(f.Courts) > 0 {
+		// รับทั้งชื่อ/alias ในทะเบียนศาล และข้อความ court เดิมที่ยังไม่ได้ผูกกับศาล
+		p := q.Args.add(f.Courts) + "::text[]"
+		conds = append(conds, "(court_id IN (SELECT resolve_court(x) FROM unnest("+p+") AS x) OR court = ANY("+p+"))")
+	}
+	if len(f.Categories) > 0 {
+		conds = append(conds, "category = ANY("+q.Args.add(f.Categories)+"::text[])")
+	}
+	// tag แม่รวม tag ลูกหลานด้วย และรับคำพ้อง/ชื่ออังกฤษได้ (ดู tag_with_descendants)
+	if len(f.Tags) > 0 && f.TagsAll {
+		for _, t := range f.Tags {
+			conds = append(conds, "tags && tag_with_descendants("+q.Args.add(t)+")")
+		}
+	} else if len(f.Tags) > 0 {
+		conds = append(conds, "tags && ARRAY(SELECT unnest(tag_with_descendants(x)) FROM unnest("+q.Args.add(f.Tags)+"::text[]) AS x)")
+	}
+	if f.DateFrom != "" {
+		conds = append(conds, "judgment_date >= "+q.Args.add(f.DateFrom)+"::date")
+	}
+	if f.DateTo != "" {
+		conds = append(conds, "judgment_date <= "+q.Args.add(f.DateTo)+"::date")
+	}
+	if f.CaseNoPrefix != "" {
+		// เลขคดีเต็ม: เทียบส่วนประกอบ (พิมพ์แบบไหนก็เจอ) ไม่งั้นเทียบข้อความขึ้นต้น
+		if n, err := legal.ParseCaseNo(f.CaseNoPrefix); err == nil {
+			conds = append(conds, "("+caseNoMatchCond(n, "", &q.Args)+")")
+		} else {
+			conds = append(conds, "case_no ILIKE "+q.Args.add(likeEscape(f.CaseNoPrefix)+"%"))
+		}
+	}
+	if f.Author != "" {
+		conds = append(conds, "created_by = "+q.Args.add(f.Author)+"::uuid")
+	}
+	for _, h := range f.Has {
+		conds = append(conds, hasFieldConds[h])
+	}
+	if len(f.Statutes) > 0 {
+		codes := make([]string, len(f.Statutes))
+		sections := make([]string, len(f.Statutes))
+		for i, r := range f.Statutes {
+			codes[i], sections[i] = r.Code, r.Section
+		}
+		// อ้างมาตราใดมาตราหนึ่งในรายการ
+		conds = append(conds, `EXISTS (SELECT 1 FROM judgment_statutes js
+			JOIN statute_sections s ON s.id = js.section_id
+			JOIN unnest(`+q.Args.add(codes)+`::text[], `+q.Args.add(sections)+`::text[]) AS f(code, section)
+			  ON s.statute_code = f.code AND (f.section = '' OR s.section = f.section)
+			WHERE js.judgment_id = judgments.id)`)
+	}
+
+	q.Where = strings.Join(conds, " AND ")
+	return q
+}
+
+// likeEscape กัน % และ _ ที่ผู้ใช้พิมพ์มาไม่ให้กลายเป็น wildcard
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
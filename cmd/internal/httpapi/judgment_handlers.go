@@ -1,36 +1,72 @@
 package httpapi
 
 import (
+	"context"
+	"errors"
 	"math"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/legal"
+	"judgment-notes/cmd/internal/storage"
+	"judgment-notes/cmd/internal/thai"
 )
 
 type Judgment struct {
-	ID           string    `json:"id"`
-	DocNo        *string   `json:"doc_no"`
-	Title        string    `json:"title"`
-	CaseNo       *string   `json:"case_no"`
-	Court        *string   `json:"court"`
-	JudgmentDate *string   `json:"judgment_date"` // YYYY-MM-DD
-	Parties      *string   `json:"parties"`
-	Facts        *string   `json:"facts"`
-	Issues       *string   `json:"issues"`
-	Holding      *string   `json:"holding"`
-	Notes        *string   `json:"notes"`
-	Tags         []string  `json:"tags"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID            string      `json:"id"`
+	DocNo         *string     `json:"doc_no"`
+	Title         string      `json:"title"`
+	CaseNo        *string     `json:"case_no"`
+	CaseNoParsed  *CaseNoInfo `json:"case_no_parsed"`          // null ถ้าไม่มี case_no หรือแยกไม่ได้
+	CaseNoError   *string     `json:"case_no_error,omitempty"` // เหตุผลที่แยก case_no ไม่ได้ (ข้อมูลเก่า)
+	Court         *string     `json:"court"`
+	CourtID       *int        `json:"court_id"` // null ถ้าชื่อศาลไม่ตรงกับทะเบียนศาล
+	Category      *string     `json:"category"`
+	JudgmentDate  *string     `json:"judgment_date"` // YYYY-MM-DD
+	Parties       *string     `json:"parties"`
+	Facts         *string     `json:"facts"`
+	Issues        *string     `json:"issues"`
+	Holding       *string     `json:"holding"`
+	Notes         *string     `json:"notes"`
+	Tags          []string    `json:"tags"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	CreatedBy     *string     `json:"created_by"`
+	CreatedByName *string     `json:"created_by_name"`
+	UpdatedBy     *string     `json:"updated_by"`
+	UpdatedByName *string     `json:"updated_by_name"`
+	DeletedAt     *time.Time  `json:"deleted_at,omitempty"`
+	DeletedBy     *string     `json:"deleted_by,omitempty"`
+	Version       int         `json:"version"`
+
+	// มีเฉพาะตอนค้นหา (?search=)
+	Rank               *float64          `json:"rank,omitempty"`
+	Highlights         map[string]string `json:"highlights,omitempty"`
+	MatchedAttachments []AttachmentMatch `json:"matched_attachments,omitempty"`
+
+	sortKeys []string      // ค่าของ column ที่ใช้เรียง ไว้สร้าง next_cursor
+	calendar thai.Calendar // ระบบปีของ JudgmentDate (ตั้งโดย localize)
+}
+
+// CaseNoInfo คือส่วนประกอบของ case_no ที่แยกแล้ว (ดู legal.ParseCaseNo)
+type CaseNoInfo struct {
+	Type       string `json:"type"` // dika / black / red / unspecified
+	Prefix     string `json:"prefix"`
+	Number     int    `json:"number"`
+	Year       int    `json:"year"` // พ.ศ.
+	Normalized string `json:"normalized"`
 }
 
 type createUpdatePayload struct {
 	Title        string   `json:"title"`
 	CaseNo       *string  `json:"case_no"`
-	Court        *string  `json:"court"`
+	Court        *string  `json:"court"`    // ชื่อหรือ alias ของศาล (แปลงเป็นชื่อมาตรฐานตอนบันทึก)
+	Category     *string  `json:"category"` // หมวด/กลุ่มงาน ใช้เลือกรูปแบบเลข doc_no
 	JudgmentDate *string  `json:"judgment_date"`
 	Parties      *string  `json:"parties"`
 	Facts        *string  `json:"facts"`
@@ -40,6 +76,34 @@ type createUpdatePayload struct {
 	Tags         []string `json:"tags"`
 }
 
+// judgmentColumns คือ column ที่ SELECT ออกมาให้ตรงกับ scanJudgment
+const judgmentColumns = `id, doc_no, title, case_no, case_type, case_prefix, case_number, case_year, case_no_error,
+       court, court_id, category, to_char(judgment_date,'YYYY-MM-DD'),
+       parties, facts, issues, holding, notes, tags, created_at, updated_at,
+       created_by, (SELECT u.name FROM users u WHERE u.id = judgments.created_by),
+       updated_by, (SELECT u.name FROM users u WHERE u.id = judgments.updated_by),
+       deleted_at, deleted_by, version`
+
+// scanJudgment อ่านแถวตาม judgmentColumns; extra คือ column ที่ SELECT ต่อท้ายเพิ่ม
+func scanJudgment(row pgx.Row, extra ...any) (Judgment, error) {
+	var j Judgment
+	var caseType, casePrefix *string
+	var caseNumber, caseYear *int
+	dest := []any{
+		&j.ID, &j.DocNo, &j.Title, &j.CaseNo, &caseType, &casePrefix, &caseNumber, &caseYear, &j.CaseNoError,
+		&j.Court, &j.CourtID, &j.Category, &j.JudgmentDate,
+		&j.Parties, &j.Facts, &j.Issues, &j.Holding, &j.Notes, &j.Tags, &j.CreatedAt, &j.UpdatedAt,
+		&j.CreatedBy, &j.CreatedByName, &j.UpdatedBy, &j.UpdatedByName,
+		&j.DeletedAt, &j.DeletedBy, &j.Version,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err == nil && caseNumber != nil && caseYear != nil {
+		n := legal.CaseNumber{Type: deref(caseType), Prefix: deref(casePrefix), Number: *caseNumber, Year: *caseYear}
+		j.CaseNoParsed = &CaseNoInfo{Type: n.Type, Prefix: n.Prefix, Number: n.Number, Year: n.Year, Normalized: n.String()}
+	}
+	return j, err
+}
+
 // Paginated response
 type PaginatedResponse struct {
 	Items      []Judgment `json:"items"`
@@ -47,80 +111,91 @@ type PaginatedResponse struct {
 	Page       int        `json:"page"`
 	Limit      int        `json:"limit"`
 	TotalPages int        `json:"totalPages"`
+	NextCursor *string    `json:"next_cursor,omitempty"` // ส่งกลับมาเป็น ?cursor= เพื่อดึงหน้าถัดไป (page เป็น 0 ในโหมด cursor)
+
+	Facets *JudgmentFacets `json:"facets,omitempty"`
 }
 
-func registerJudgmentRoutes(api *gin.RouterGroup, pool *pgxpool.Pool) {
+func registerJudgmentRoutes(api *gin.RouterGroup, pool *pgxpool.Pool, store storage.Store) {
 	// public read
 	api.GET("/judgments", func(c *gin.Context) { listJudgments(c, pool) })
+	api.GET("/judgments/case-no", func(c *gin.Context) { caseNoLookup(c, pool) })
 	api.GET("/judgments/:id", func(c *gin.Context) { getJudgment(c, pool) })
+	registerJudgmentExportRoutes(api, pool)
 
 	// ✅ auth write (user ก็ทำ CRUD ได้ แค่ต้อง login)
 	auth := api.Group("")
-	auth.Use(AuthMiddleware())
+	auth.Use(AuthMiddleware(pool), requireMethodScope(scopeJudgmentsRead, scopeJudgmentsWrite))
 	auth.POST("/judgments", func(c *gin.Context) { createJudgment(c, pool) })
 	auth.PUT("/judgments/:id", func(c *gin.Context) { updateJudgment(c, pool) })
+	auth.PATCH("/judgments/:id", func(c *gin.Context) { patchJudgment(c, pool) })
+	auth.POST("/judgments/import", func(c *gin.Context) { importJudgmentsHandler(c, pool) })
 	auth.DELETE("/judgments/:id", func(c *gin.Context) { deleteJudgment(c, pool) })
+	registerJudgmentRevisionRoutes(auth, pool)
+	registerJudgmentAttachmentRoutes(api, auth, pool, store)
+	registerJudgmentCitationRoutes(api, auth, pool)
 }
 
 func listJudgments(c *gin.Context, pool *pgxpool.Pool) {
-	search := strings.TrimSpace(c.Query("search"))
-
-	// Pagination params
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	// Validate
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
 	}
-	if limit > 100 {
-		limit = 100 // max limit
+	f, err := parseJudgmentFilter(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
 	}
 
-	offset := (page - 1) * limit
+	page, limit, offset := paginationParams(c)
 
-	// Build WHERE clause
-	conds := []string{"1=1"}
-	args := []any{}
-	argN := 1
+	// Build WHERE clause (ซ่อนรายการที่อยู่ในถังขยะ)
+	jq := f.build()
 
-	if search != "" {
-		conds = append(conds,
-			"(doc_no ILIKE $"+itoa(argN)+" OR title ILIKE $"+itoa(argN)+" OR case_no ILIKE $"+itoa(argN)+" OR court ILIKE $"+itoa(argN)+" OR notes ILIKE $"+itoa(argN)+")",
-		)
-		args = append(args, "%"+search+"%")
-		argN++
+	sort, err := parseJudgmentSort(c, jq)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
 	}
 
-	where := strings.Join(conds, " AND ")
+	// ?cursor= (ว่างได้ = หน้าแรก) ใช้ keyset pagination แทน page/offset
+	_, cursorMode := c.GetQuery("cursor")
+	var cursor *judgmentCursor
+	if s := c.Query("cursor"); s != "" {
+		cur, err := decodeJudgmentCursor(s, sort)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		cursor = &cur
+	}
 
 	// Count total
-	countQ := `SELECT COUNT(*) FROM judgments WHERE ` + where
+	countQ := `SELECT COUNT(*) FROM judgments WHERE ` + jq.Where
 	var total int
-	if err := pool.QueryRow(c, countQ, args...).Scan(&total); err != nil {
+	if err := pool.QueryRow(c, countQ, jq.Args...).Scan(&total); err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Calculate total pages
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
-	if totalPages < 1 {
-		totalPages = 1
+	totalPages := totalPagesFor(total, limit)
+
+	// Fetch items with pagination (ดึงเกิน 1 แถวไว้รู้ว่ามีหน้าถัดไปไหม)
+	args := append(sqlArgs{}, jq.Args...)
+	where := jq.Where
+	if cursorMode {
+		page, offset = 0, 0
+		if cursor != nil {
+			where += " AND " + sort.after(*cursor, &args)
+		}
 	}
-
-	// Fetch items with pagination
 	q := `
-SELECT id, doc_no, title, case_no, court, to_char(judgment_date,'YYYY-MM-DD'),
-       parties, facts, issues, holding, notes, tags, created_at, updated_at
+SELECT ` + judgmentColumns + `, ` + jq.RankExpr + `, ` + sort.selectKeys() + `
 FROM judgments
 WHERE ` + where + `
-ORDER BY judgment_date DESC NULLS LAST, updated_at DESC
-LIMIT $` + itoa(argN) + ` OFFSET $` + itoa(argN+1)
-
-	args = append(args, limit, offset)
+ORDER BY ` + sort.orderBy() + `
+LIMIT ` + args.add(limit+1) + ` OFFSET ` + args.add(offset)
 
 	rows, err := pool.Query(c, q, args...)
 	if err != nil {
@@ -130,19 +205,53 @@ LIMIT $` + itoa(argN) + ` OFFSET $` + itoa(argN+1)
 	defer rows.Close()
 
 	items := make([]Judgment, 0)
+	var nextCursor *string
 	for rows.Next() {
-		var j Judgment
-		var jd *string
-		if err := rows.Scan(
-			&j.ID, &j.DocNo, &j.Title, &j.CaseNo, &j.Court, &jd,
-			&j.Parties, &j.Facts, &j.Issues, &j.Holding, &j.Notes, &j.Tags, &j.CreatedAt, &j.UpdatedAt,
-		); err != nil {
+		var rank float64
+		keys := make([]string, len(sort.Cols))
+		dest := []any{&rank}
+		for i := range keys {
+			dest = append(dest, &keys[i])
+		}
+		j, err := scanJudgment(rows, dest...)
+		if err != nil {
 			c.JSON(500, gin.H{"error": err.Error()})
 			return
 		}
-		j.JudgmentDate = jd
+		if len(items) == limit {
+			// มีแถวถัดไป: cursor ชี้ที่แถวสุดท้ายของหน้านี้
+			last := items[len(items)-1]
+			next := judgmentCursor{Key: sort.Key, Desc: sort.Desc, Values: last.sortKeys}.encode()
+			nextCursor = &next
+			break
+		}
+		if jq.Terms != nil {
+			j.Rank = &rank
+			j.Highlights = judgmentHighlights(j, jq.Terms)
+		}
+		j.sortKeys = keys
+		j.localize(cal)
 		items = append(items, j)
 	}
+	rows.Close()
+
+	// ไฟล์แนบที่ตรงกับคำค้น
+	if jq.Terms != nil && len(items) > 0 {
+		if err := attachAttachmentMatches(c, pool, items, jq); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// facet สำหรับ sidebar (ปิดได้ด้วย ?facets=false)
+	var facets *JudgmentFacets
+	if c.DefaultQuery("facets", "true") != "false" {
+		if facets, err = loadJudgmentFacets(c, pool, jq); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		facets.localize(cal)
+	}
 
 	c.JSON(200, PaginatedResponse{
 		Items:      items,
@@ -150,101 +259,358 @@ LIMIT $` + itoa(argN) + ` OFFSET $` + itoa(argN+1)
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		NextCursor: nextCursor,
+		Facets:     facets,
 	})
 }
 
+// paginationParams อ่าน page/limit จาก query (limit สูงสุด 100)
+func paginationParams(c *gin.Context) (page, limit, offset int) {
+	page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	// Validate
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100 // max limit
+	}
+
+	return page, limit, (page - 1) * limit
+}
+
+func totalPagesFor(total, limit int) int {
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	return totalPages
+}
+
 func getJudgment(c *gin.Context, pool *pgxpool.Pool) {
 	id := c.Param("id")
+	if pdfID, ok := strings.CutSuffix(id, ".pdf"); ok {
+		judgmentPDF(c, pool, pdfID)
+		return
+	}
+
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
 
 	q := `
-SELECT id, doc_no, title, case_no, court, to_char(judgment_date,'YYYY-MM-DD'),
-       parties, facts, issues, holding, notes, tags, created_at, updated_at
+SELECT ` + judgmentColumns + `
 FROM judgments
-WHERE id=$1`
+WHERE id=$1 AND deleted_at IS NULL`
 
-	var j Judgment
-	var jd *string
-	err := pool.QueryRow(c, q, id).Scan(
-		&j.ID, &j.DocNo, &j.Title, &j.CaseNo, &j.Court, &jd,
-		&j.Parties, &j.Facts, &j.Issues, &j.Holding, &j.Notes, &j.Tags, &j.CreatedAt, &j.UpdatedAt,
-	)
+	j, err := scanJudgment(pool.QueryRow(c, q, id))
 	if err != nil {
 		c.JSON(404, gin.H{"error": "not found"})
 		return
 	}
-	j.JudgmentDate = jd
+	j.localize(cal)
+
+	etag := judgmentETag(j.Version, cal)
+	c.Header("ETag", etag)
+	c.Header("Vary", "X-Calendar") // ระบบปีเลือกผ่าน header ได้ด้วย
+	if inm := c.GetHeader("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+		c.Status(304)
+		return
+	}
 	c.JSON(200, j)
 }
 
 func createJudgment(c *gin.Context, pool *pgxpool.Pool) {
 	var in createUpdatePayload
-	if err := c.ShouldBindJSON(&in); err != nil || strings.TrimSpace(in.Title) == "" {
+	if err := c.ShouldBindJSON(&in); err != nil {
 		c.JSON(400, gin.H{"error": "invalid payload (title required)"})
 		return
 	}
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	if err := validateJudgmentPayload(&in, cal); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateCaseNo(&in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
-	q := `
-INSERT INTO judgments (doc_no, title, case_no, court, judgment_date, parties, facts, issues, holding, notes, tags)
-VALUES (next_judgment_doc_no(), $1,$2,$3,$4::date,$5,$6,$7,$8,$9,$10)
-RETURNING id, doc_no`
-
-	var id string
-	var docNo string
-	err := pool.QueryRow(c, q,
-		in.Title, in.CaseNo, in.Court, in.JudgmentDate,
-		in.Parties, in.Facts, in.Issues, in.Holding, in.Notes, in.Tags,
-	).Scan(&id, &docNo)
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	id, docNo, err := insertJudgment(c, tx, in, c.GetString("userID"))
+	if err != nil {
+		judgmentWriteError(c, err)
+		return
+	}
+	// เลขคดีซ้ำกับที่มีอยู่: ยังบันทึกให้ แต่แจ้งกลับไปให้ตรวจ
+	dups, err := caseNoDuplicates(c, tx, id)
 	if err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := gin.H{"id": id, "doc_no": docNo}
+	if len(dups) > 0 {
+		localizeRefs(dups, cal)
+		resp["duplicates"] = dups
+	}
+	c.JSON(201, resp)
+}
+
+// insertJudgment สร้าง judgment ใหม่พร้อมออกเลข doc_no, บันทึก revision แรก และ index
+// (payload ต้องผ่าน validateJudgmentPayload มาแล้ว)
+func insertJudgment(ctx context.Context, tx pgx.Tx, in createUpdatePayload, userID string) (id, docNo string, err error) {
+	courtID, court, err := resolveCourt(ctx, tx, in.Court)
+	if err != nil {
+		return "", "", err
+	}
+
+	if in.Tags, err = resolveTags(ctx, tx, in.Tags, tagAutoCreate); err != nil {
+		return "", "", err
+	}
+	docNo, _, err = nextDocNo(ctx, tx, courtID, in.Category, in.JudgmentDate, true)
+	if err != nil {
+		return "", "", err
+	}
+
+	q := `
+INSERT INTO judgments (doc_no, title, case_no, court, court_id, category, judgment_date, parties, facts, issues, holding, notes, tags, created_by, updated_by)
+VALUES ($1,$2,$3,$4,$5,$6,$7::date,$8,$9,$10,$11,$12,$13,$14,$14)
+RETURNING id`
+
+	err = tx.QueryRow(ctx, q,
+		docNo, in.Title, in.CaseNo, court, courtID, in.Category, in.JudgmentDate,
+		in.Parties, in.Facts, in.Issues, in.Holding, in.Notes, in.Tags, nullIfEmpty(userID),
+	).Scan(&id)
+	if err != nil {
+		return "", "", err
+	}
+	if err := recordRevision(ctx, tx, id, "create", userID); err != nil {
+		return "", "", err
+	}
+	if err := reindexJudgment(ctx, tx, id); err != nil {
+		return "", "", err
+	}
+	return id, docNo, nil
+}
+
+// validateJudgmentPayload: กติกาเดียวกันทั้ง create / update / patch / import
+// ปีของ judgment_date ที่ไม่ได้กำกับ พ.ศ./ค.ศ. อ่านตาม cal
+func validateJudgmentPayload(in *createUpdatePayload, cal thai.Calendar) error {
+	if strings.TrimSpace(in.Title) == "" {
+		return errors.New("invalid payload (title required)")
+	}
+	if in.JudgmentDate != nil {
+		d := strings.TrimSpace(*in.JudgmentDate)
+		if d == "" {
+			in.JudgmentDate = nil
+		} else if iso, err := parseDateInput(d, cal); err != nil {
+			return errors.New("judgment_date must be a date such as 2024-03-15 or 15 มีนาคม พ.ศ. 2567 (send calendar=be for 2567-03-15): " + err.Error())
+		} else {
+			in.JudgmentDate = &iso
+		}
+	}
+	if in.Category != nil {
+		if s := strings.TrimSpace(*in.Category); s == "" {
+			in.Category = nil
+		} else {
+			in.Category = &s
+		}
+	}
+	if in.Tags == nil {
+		in.Tags = []string{}
+	}
+	return nil
+}
 
-	c.JSON(201, gin.H{"id": id, "doc_no": docNo})
+// validateCaseNo: case_no ที่ส่งมาต้องแยกเป็นเลขคดีได้ (ใช้ตอนสร้าง และตอนแก้ case_no เท่านั้น
+// ข้อมูลเก่าที่แยกไม่ได้จึงยังแก้ field อื่นได้ตามปกติ)
+func validateCaseNo(in *createUpdatePayload) error {
+	if in.CaseNo == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*in.CaseNo)
+	if v == "" {
+		in.CaseNo = nil
+		return nil
+	}
+	if _, err := legal.ParseCaseNo(v); err != nil {
+		return errors.New("case_no: " + err.Error())
+	}
+	in.CaseNo = &v
+	return nil
 }
 
 func updateJudgment(c *gin.Context, pool *pgxpool.Pool) {
 	id := c.Param("id")
 
 	var in createUpdatePayload
-	if err := c.ShouldBindJSON(&in); err != nil || strings.TrimSpace(in.Title) == "" {
+	if err := c.ShouldBindJSON(&in); err != nil {
 		c.JSON(400, gin.H{"error": "invalid payload (title required)"})
 		return
 	}
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	if err := validateJudgmentPayload(&in, cal); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
-	q := `
-UPDATE judgments
-SET title=$1, case_no=$2, court=$3, judgment_date=$4::date, parties=$5, facts=$6,
-    issues=$7, holding=$8, notes=$9, tags=$10, updated_at=now()
-WHERE id=$11`
-
-	ct, err := pool.Exec(c, q,
-		in.Title, in.CaseNo, in.Court, in.JudgmentDate,
-		in.Parties, in.Facts, in.Issues, in.Holding, in.Notes, in.Tags, id,
-	)
+	tx, err := pool.Begin(c)
 	if err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
-	if ct.RowsAffected() == 0 {
-		c.JSON(404, gin.H{"error": "not found"})
+	defer tx.Rollback(c)
+
+	if !lockJudgmentForWrite(c, tx, id) {
+		return
+	}
+	var current *string
+	if err := tx.QueryRow(c, `SELECT case_no FROM judgments WHERE id=$1`, id).Scan(&current); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if deref(current) != deref(in.CaseNo) {
+		if err := validateCaseNo(&in); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := writeJudgment(c, tx, id, in, "update"); err != nil {
+		judgmentWriteError(c, err)
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(204)
+	getJudgment(c, pool)
+}
+
+// judgmentWriteError ตอบ error จาก insertJudgment / writeJudgment (tag ที่ไม่รู้จัก = 400)
+func judgmentWriteError(c *gin.Context, err error) {
+	var unknown *unknownTagsError
+	if errors.As(err, &unknown) {
+		c.JSON(400, gin.H{"error": err.Error(), "unknown_tags": unknown.tags})
+		return
+	}
+	c.JSON(500, gin.H{"error": err.Error()})
+}
+
+// writeJudgment เขียนทับทุก field ตาม payload แล้วบันทึก revision ใน tx เดียวกัน
+// (ต้องผ่าน lockJudgmentForWrite มาก่อน)
+func writeJudgment(c *gin.Context, tx pgx.Tx, id string, in createUpdatePayload, action string) error {
+	userID := c.GetString("userID")
+	courtID, court, err := resolveCourt(c, tx, in.Court)
+	if err != nil {
+		return err
+	}
+	// restore: tag ใน revision เก่าอาจถูกลบจากทะเบียนไปแล้ว ให้สร้างคืน
+	if in.Tags, err = resolveTags(c, tx, in.Tags, tagAutoCreate || action == "restore"); err != nil {
+		return err
+	}
+
+	q := `
+UPDATE judgments
+SET title=$1, case_no=$2, court=$3, court_id=$4, category=$5, judgment_date=$6::date, parties=$7, facts=$8,
+    issues=$9, holding=$10, notes=$11, tags=$12, updated_by=$13, updated_at=now(),
+    version=version+1
+WHERE id=$14`
+
+	if _, err := tx.Exec(c, q,
+		in.Title, in.CaseNo, court, courtID, in.Category, in.JudgmentDate,
+		in.Parties, in.Facts, in.Issues, in.Holding, in.Notes, in.Tags, userID, id,
+	); err != nil {
+		return err
+	}
+	if err := recordRevision(c, tx, id, action, userID); err != nil {
+		return err
+	}
+	return reindexJudgment(c, tx, id)
 }
 
 func deleteJudgment(c *gin.Context, pool *pgxpool.Pool) {
 	id := c.Param("id")
 
-	ct, err := pool.Exec(c, `DELETE FROM judgments WHERE id=$1`, id)
+	tx, err := pool.Begin(c)
 	if err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
-	if ct.RowsAffected() == 0 {
-		c.JSON(404, gin.H{"error": "not found"})
+	defer tx.Rollback(c)
+
+	if !lockJudgmentForWrite(c, tx, id) {
+		return
+	}
+
+	// ย้ายไปถังขยะ (admin กู้คืนหรือ purge ได้ภายหลัง)
+	userID := c.GetString("userID")
+	if _, err := tx.Exec(c, `UPDATE judgments SET deleted_at=now(), deleted_by=$2, version=version+1 WHERE id=$1`, id, nullIfEmpty(userID)); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := recordRevision(c, tx, id, "delete", userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.Status(204)
 }
+
+// canEditJudgment: แก้/ลบได้เฉพาะคนสร้าง หรือ admin
+// (ข้อมูลเก่าที่ไม่มี created_by จะแก้ได้เฉพาะ admin)
+func canEditJudgment(c *gin.Context, createdBy *string) bool {
+	if hasRole(c, "admin") {
+		return true
+	}
+	userID := c.GetString("userID")
+	return createdBy != nil && userID != "" && *createdBy == userID
+}
+
+// lockJudgmentForWrite ล็อกแถวไว้ใน tx แล้วเช็คสิทธิ์และ If-Match ถ้าไม่ผ่านจะตอบ error ให้เลยและคืน false
+func lockJudgmentForWrite(c *gin.Context, tx pgx.Tx, id string) bool {
+	var createdBy *string
+	var version int
+	err := tx.QueryRow(c, `SELECT created_by, version FROM judgments WHERE id=$1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&createdBy, &version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return false
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	if !canEditJudgment(c, createdBy) {
+		c.JSON(403, gin.H{"error": "only the author or an admin can modify this judgment"})
+		return false
+	}
+	return checkIfMatch(c, version, func() (Judgment, error) {
+		return scanJudgment(tx.QueryRow(c, `SELECT `+judgmentColumns+` FROM judgments WHERE id=$1`, id))
+	})
+}
@@ -0,0 +1,382 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/importer"
+	"judgment-notes/cmd/internal/office"
+	"judgment-notes/cmd/internal/thai"
+)
+
+// ขนาดไฟล์นำเข้าสูงสุด (IMPORT_MAX_BYTES, ค่าเริ่มต้น 50MB)
+var importMaxBytes = int64(envInt("IMPORT_MAX_BYTES", 50<<20))
+
+const defaultImportChunkSize = 500
+
+// field ของ judgment ที่นำเข้าได้ (ชื่อเดียวกับ JSON ของ createUpdatePayload)
+var importFields = []string{
+	"title", "case_no", "court", "category", "judgment_date", "parties",
+	"facts", "issues", "holding", "notes", "tags",
+}
+
+type ImportOptions struct {
+	DryRun    bool              // validate + ลอง insert แล้ว rollback ทั้งหมด
+	Chunked   bool              // false = ทั้งไฟล์ใน tx เดียว มีแถวไหนพังก็ไม่นำเข้าเลย
+	ChunkSize int               // จำนวนแถวต่อ tx เมื่อ Chunked
+	Mapping   map[string]string // field ของ judgment → ชื่อ column ในไฟล์ (ไม่ระบุ = ชื่อเดียวกัน)
+	UserID    string            // เจ้าของรายการที่นำเข้า (created_by)
+	Calendar  thai.Calendar     // ระบบปีของ judgment_date ที่ไม่ได้เขียน พ.ศ./ค.ศ. กำกับ
+}
+
+type ImportRowResult struct {
+	Row    int      `json:"row"` // เลขแถว/บรรทัดในไฟล์
+	OK     bool     `json:"ok"`
+	ID     string   `json:"id,omitempty"`
+	DocNo  string   `json:"doc_no,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ImportReport struct {
+	Total    int               `json:"total"`
+	Valid    int               `json:"valid"`    // แถวที่ผ่านทั้ง validate และ insert
+	Imported int               `json:"imported"` // แถวที่ commit แล้วจริง
+	Failed   int               `json:"failed"`
+	DryRun   bool              `json:"dry_run"`
+	Mode     string            `json:"mode"` // atomic / chunked
+	Rows     []ImportRowResult `json:"rows"`
+}
+
+func envInt(key string, fallback int) int {
+	n, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// importJudgmentsHandler: POST /api/judgments/import (multipart)
+// form: file, format (csv|ndjson|xlsx), mapping (JSON), dry_run, mode (atomic|chunked), chunk_size
+func importJudgmentsHandler(c *gin.Context, pool *pgxpool.Pool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importMaxBytes)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(400, gin.H{"error": "file is required"})
+		return
+	}
+	format, err := importer.DetectFormat(fh.Filename, c.PostForm("format"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	opts := ImportOptions{
+		DryRun:    c.PostForm("dry_run") == "true",
+		ChunkSize: defaultImportChunkSize,
+		UserID:    c.GetString("userID"),
+		Calendar:  cal,
+	}
+	switch c.DefaultPostForm("mode", "atomic") {
+	case "atomic":
+	case "chunked":
+		opts.Chunked = true
+	default:
+		c.JSON(400, gin.H{"error": "mode must be atomic or chunked"})
+		return
+	}
+	if s := c.PostForm("chunk_size"); s != "" {
+		if opts.ChunkSize, err = strconv.Atoi(s); err != nil || opts.ChunkSize < 1 {
+			c.JSON(400, gin.H{"error": "chunk_size must be a positive number"})
+			return
+		}
+	}
+	if s := c.PostForm("mapping"); s != "" {
+		if err := json.Unmarshal([]byte(s), &opts.Mapping); err != nil {
+			c.JSON(400, gin.H{"error": "mapping must be a JSON object of field → column"})
+			return
+		}
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	rows, err := importer.Read(f, format)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "cannot read file: " + err.Error()})
+		return
+	}
+
+	report, err := ImportJudgments(c, pool, rows, opts)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := 200
+	if !opts.DryRun && !opts.Chunked && report.Failed > 0 {
+		status = 422 // atomic: มีแถวพัง จึงไม่ได้นำเข้าเลย
+	}
+	c.JSON(status, report)
+}
+
+// ImportJudgments นำเข้าแถวทั้งหมดด้วยกติกาเดียวกับ createJudgment (ใช้ทั้ง API และ CLI)
+func ImportJudgments(ctx context.Context, pool *pgxpool.Pool, rows []importer.Row, opts ImportOptions) (ImportReport, error) {
+	report := ImportReport{Total: len(rows), DryRun: opts.DryRun, Mode: "atomic", Rows: make([]ImportRowResult, len(rows))}
+	if opts.Chunked {
+		report.Mode = "chunked"
+	}
+	if opts.ChunkSize < 1 {
+		opts.ChunkSize = defaultImportChunkSize
+	}
+	for field := range opts.Mapping {
+		if !containsString(importFields, field) {
+			return report, errors.New("unknown field in mapping: " + field)
+		}
+	}
+
+	payloads := make([]createUpdatePayload, len(rows))
+	pending := []int{} // index ของแถวที่ validate ผ่าน
+	for i, row := range rows {
+		report.Rows[i].Row = row.Line
+		in, errs := importRowPayload(row, opts.Mapping)
+		if len(errs) == 0 {
+			if err := validateJudgmentPayload(&in, opts.Calendar); err != nil {
+				errs = append(errs, err.Error())
+			}
+			if err := validateCaseNo(&in); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			report.Rows[i].Errors = errs
+			continue
+		}
+		payloads[i] = in
+		pending = append(pending, i)
+	}
+
+	// atomic / dry-run ใช้ tx เดียวทั้งไฟล์, chunked แบ่ง commit ทีละ chunk
+	chunk := len(pending)
+	if opts.Chunked && !opts.DryRun {
+		chunk = opts.ChunkSize
+	}
+	for start := 0; start < len(pending); start += chunk {
+		end := min(start+chunk, len(pending))
+		if err := importChunk(ctx, pool, pending[start:end], payloads, &report, opts); err != nil {
+			return report, err
+		}
+	}
+
+	for _, r := range report.Rows {
+		if r.OK {
+			report.Valid++
+		} else {
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+func importChunk(ctx context.Context, pool *pgxpool.Pool, idx []int, payloads []createUpdatePayload, report *ImportReport, opts ImportOptions) error {
+	if len(idx) == 0 {
+		return nil
+	}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, i := range idx {
+		// savepoint ต่อแถว: แถวที่ insert ไม่ผ่านไม่ทำให้ทั้ง tx พัง
+		id, docNo, err := func() (string, string, error) {
+			sp, err := tx.Begin(ctx)
+			if err != nil {
+				return "", "", err
+			}
+			defer sp.Rollback(ctx)
+			id, docNo, err := insertJudgment(ctx, sp, payloads[i], opts.UserID)
+			if err != nil {
+				return "", "", err
+			}
+			return id, docNo, sp.Commit(ctx)
+		}()
+		if err != nil {
+			report.Rows[i].Errors = append(report.Rows[i].Errors, err.Error())
+			continue
+		}
+		report.Rows[i].OK = true
+		if !opts.DryRun {
+			report.Rows[i].ID, report.Rows[i].DocNo = id, docNo
+		}
+	}
+
+	if opts.DryRun {
+		return nil // rollback ตอน defer
+	}
+	if !opts.Chunked && hasFailedRow(report.Rows) {
+		// atomic: ไม่นำเข้าเลยถ้ามีแถวไหนพัง
+		for i := range report.Rows {
+			report.Rows[i].ID, report.Rows[i].DocNo = "", ""
+		}
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	for _, i := range idx {
+		if report.Rows[i].OK {
+			report.Imported++
+		}
+	}
+	return nil
+}
+
+func hasFailedRow(rows []ImportRowResult) bool {
+	for _, r := range rows {
+		if !r.OK {
+			return true
+		}
+	}
+	return false
+}
+
+// importRowPayload map column ของแถวเป็น payload (ยังไม่ validate)
+func importRowPayload(row importer.Row, mapping map[string]string) (createUpdatePayload, []string) {
+	var in createUpdatePayload
+	errs := []string{}
+
+	targets := map[string]**string{
+		"case_no":       &in.CaseNo,
+		"court":         &in.Court,
+		"category":      &in.Category,
+		"judgment_date": &in.JudgmentDate,
+		"parties":       &in.Parties,
+		"facts":         &in.Facts,
+		"issues":        &in.Issues,
+		"holding":       &in.Holding,
+		"notes":         &in.Notes,
+	}
+
+	for _, field := range importFields {
+		col := field
+		if m, ok := mapping[field]; ok && strings.TrimSpace(m) != "" {
+			col = m
+		}
+		v, ok := row.Get(col)
+		if !ok || v == nil {
+			continue
+		}
+
+		switch field {
+		case "title":
+			s, err := importString(v)
+			if err != nil {
+				errs = append(errs, field+": "+err.Error())
+			}
+			in.Title = s
+		case "tags":
+			tags, err := importTags(v)
+			if err != nil {
+				errs = append(errs, field+": "+err.Error())
+			}
+			in.Tags = tags
+		default:
+			s, err := importString(v)
+			if err != nil {
+				errs = append(errs, field+": "+err.Error())
+				continue
+			}
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			// วันที่จาก Excel มักเป็น serial number
+			if field == "judgment_date" && isDigits(s) && len(s) <= 6 {
+				if d, ok := office.ExcelSerialDate(s); ok {
+					s = d.Format("2006-01-02")
+				}
+			}
+			*targets[field] = &s
+		}
+	}
+	return in, errs
+}
+
+func importString(v any) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	}
+	return "", fmt.Errorf("must be text, got %T", v)
+}
+
+// importTags รับได้ทั้ง array และข้อความคั่นด้วย , ; หรือ |
+func importTags(v any) ([]string, error) {
+	switch x := v.(type) {
+	case []any:
+		out := []string{}
+		for _, t := range x {
+			s, err := importString(t)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s)
+		}
+		return dedupeTags(out), nil
+	case string:
+		parts := strings.FieldsFunc(x, func(r rune) bool { return r == ',' || r == ';' || r == '|' })
+		return dedupeTags(parts), nil
+	}
+	return nil, fmt.Errorf("must be a list, got %T", v)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveUserID หา id ของ user จากอีเมล (ใช้กับ CLI)
+func ResolveUserID(ctx context.Context, pool *pgxpool.Pool, email string) (string, error) {
+	var id string
+	err := pool.QueryRow(ctx, `SELECT id FROM users WHERE email=$1`, strings.ToLower(strings.TrimSpace(email))).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", errors.New("user not found: " + email)
+	}
+	return id, err
+}
@@ -0,0 +1,210 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+// tagOps: นอกจากส่ง array มาแทนทั้งชุด ยังส่ง {"add": [...], "remove": [...]} ได้
+type tagOps struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+}
+
+// patchJudgment: PATCH /api/judgments/:id แบบ JSON Merge Patch (RFC 7396)
+// field ที่ไม่ส่งมาจะไม่ถูกแตะ, ส่ง null = ล้างค่า
+func patchJudgment(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+
+	raw, err := c.GetRawData()
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &patch); err != nil || patch == nil {
+		c.JSON(400, gin.H{"error": "invalid payload (merge patch must be a JSON object)"})
+		return
+	}
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	if !lockJudgmentForWrite(c, tx, id) {
+		return
+	}
+
+	in, err := loadJudgmentPayload(c, tx, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	canonical := func(tags []string) ([]string, error) {
+		known, _, err := lookupTags(c, tx, tags)
+		return known, err
+	}
+	if err := applyMergePatch(&in, patch, canonical); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	// วันที่เดิมที่โหลดจาก DB เป็น ค.ศ. เสมอ ใช้ระบบปีของ request เฉพาะเมื่อ patch ส่ง judgment_date มา
+	dateCal := thai.Gregorian
+	if _, ok := patch["judgment_date"]; ok {
+		dateCal = cal
+	}
+	if err := validateJudgmentPayload(&in, dateCal); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := patch["case_no"]; ok {
+		if err := validateCaseNo(&in); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if len(patch) > 0 {
+		if err := writeJudgment(c, tx, id, in, "update"); err != nil {
+			judgmentWriteError(c, err)
+			return
+		}
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	getJudgment(c, pool)
+}
+
+// loadJudgmentPayload อ่านค่าปัจจุบันในรูปแบบเดียวกับ payload ของ PUT
+func loadJudgmentPayload(ctx context.Context, q querier, id string) (createUpdatePayload, error) {
+	var in createUpdatePayload
+	var raw []byte
+	if err := q.QueryRow(ctx, `SELECT `+snapshotSQL+` FROM judgments j WHERE j.id=$1`, id).Scan(&raw); err != nil {
+		return in, err
+	}
+	err := json.Unmarshal(raw, &in)
+	return in, err
+}
+
+// applyMergePatch แก้ in ตาม patch; canonical แปลง tag เป็นชื่อมาตรฐาน (ใช้กับ tags.remove) nil = ไม่แปลง
+func applyMergePatch(in *createUpdatePayload, patch map[string]json.RawMessage, canonical func([]string) ([]string, error)) error {
+	nullable := map[string]**string{
+		"case_no":       &in.CaseNo,
+		"court":         &in.Court,
+		"category":      &in.Category,
+		"judgment_date": &in.JudgmentDate,
+		"parties":       &in.Parties,
+		"facts":         &in.Facts,
+		"issues":        &in.Issues,
+		"holding":       &in.Holding,
+		"notes":         &in.Notes,
+	}
+
+	for key, val := range patch {
+		isNull := bytes.Equal(bytes.TrimSpace(val), []byte("null"))
+
+		switch key {
+		case "title":
+			var s string
+			if isNull || json.Unmarshal(val, &s) != nil || strings.TrimSpace(s) == "" {
+				return errors.New("title must be a non-empty string")
+			}
+			in.Title = s
+
+		case "tags":
+			tags, err := patchTags(in.Tags, val, isNull, canonical)
+			if err != nil {
+				return err
+			}
+			in.Tags = tags
+
+		default:
+			dst, ok := nullable[key]
+			if !ok {
+				return errors.New("unknown or read-only field: " + key)
+			}
+			if isNull {
+				*dst = nil
+				continue
+			}
+			var s string
+			if err := json.Unmarshal(val, &s); err != nil {
+				return errors.New(key + " must be a string or null")
+			}
+			*dst = &s
+		}
+	}
+	return nil
+}
+
+func patchTags(current []string, val json.RawMessage, isNull bool, canonical func([]string) ([]string, error)) ([]string, error) {
+	if isNull {
+		return []string{}, nil
+	}
+
+	var replace []string
+	if err := json.Unmarshal(val, &replace); err == nil {
+		return dedupeTags(replace), nil
+	}
+
+	var ops tagOps
+	dec := json.NewDecoder(bytes.NewReader(val))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&ops); err != nil {
+		return nil, errors.New(`tags must be an array, null, or {"add": [...], "remove": [...]}`)
+	}
+
+	// ลบด้วย synonym/ชื่ออังกฤษได้: tag ที่เก็บไว้เป็นชื่อมาตรฐานแล้ว จึงเทียบทั้งค่าที่ส่งมาและชื่อมาตรฐานของมัน
+	remove := map[string]bool{}
+	names := ops.Remove
+	if canonical != nil && len(ops.Remove) > 0 {
+		resolved, err := canonical(ops.Remove)
+		if err != nil {
+			return nil, err
+		}
+		names = append(append([]string{}, ops.Remove...), resolved...)
+	}
+	for _, t := range names {
+		remove[strings.TrimSpace(t)] = true
+	}
+	out := []string{}
+	for _, t := range append(append([]string{}, current...), ops.Add...) {
+		if !remove[strings.TrimSpace(t)] {
+			out = append(out, t)
+		}
+	}
+	return dedupeTags(out), nil
+}
+
+// dedupeTags ตัดช่องว่าง ตัด tag ว่าง และตัดตัวซ้ำ (คงลำดับเดิม)
+func dedupeTags(tags []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	str := func(s string) *string { return &s }
+	base := func() createUpdatePayload {
+		return createUpdatePayload{
+			Title: "เดิม", CaseNo: str("1234/2565"), Court: str("ศาลฎีกา"), Notes: str("หมายเหตุ"),
+			Tags: []string{"อาญา", "ลักทรัพย์"},
+		}
+	}
+	// synonym → ชื่อมาตรฐาน แทน tag registry
+	canonical := func(tags []string) ([]string, error) {
+		syn := map[string]string{"criminal": "อาญา", "theft": "ลักทรัพย์"}
+		out := []string{}
+		for _, t := range tags {
+			if n, ok := syn[t]; ok {
+				out = append(out, n)
+			}
+		}
+		return out, nil
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    func(*createUpdatePayload)
+		wantErr bool
+	}{
+		{"empty patch", `{}`, func(*createUpdatePayload) {}, false},
+		{"set string", `{"title": "ใหม่", "court": "ศาลอุทธรณ์"}`, func(p *createUpdatePayload) {
+			p.Title, p.Court = "ใหม่", str("ศาลอุทธรณ์")
+		}, false},
+		{"null deletes", `{"notes": null, "case_no": null}`, func(p *createUpdatePayload) {
+			p.Notes, p.CaseNo = nil, nil
+		}, false},
+		{"tags null clears", `{"tags": null}`, func(p *createUpdatePayload) { p.Tags = []string{} }, false},
+		{"tags replace", `{"tags": [" แพ่ง ", "แพ่ง", ""]}`, func(p *createUpdatePayload) { p.Tags = []string{"แพ่ง"} }, false},
+		{"tags add and remove", `{"tags": {"add": ["แพ่ง"], "remove": ["อาญา"]}}`, func(p *createUpdatePayload) {
+			p.Tags = []string{"ลักทรัพย์", "แพ่ง"}
+		}, false},
+		{"tags remove by synonym", `{"tags": {"remove": ["theft", "ไม่มี"]}}`, func(p *createUpdatePayload) {
+			p.Tags = []string{"อาญา"}
+		}, false},
+		{"title null", `{"title": null}`, nil, true},
+		{"title blank", `{"title": "  "}`, nil, true},
+		{"wrong type", `{"notes": 5}`, nil, true},
+		{"read-only field", `{"doc_no": "JG-1"}`, nil, true},
+		{"tags bad ops", `{"tags": {"replace": ["x"]}}`, nil, true},
+	}
+	for _, tt := range tests {
+		var patch map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+			t.Fatal(err)
+		}
+		got := base()
+		err := applyMergePatch(&got, patch, canonical)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		want := base()
+		tt.want(&want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/brief"
+)
+
+// PDF ต้องสร้างทั้งไฟล์ในหน่วยความจำ จึงจำกัดจำนวนเรื่องต่อไฟล์ (PDF_BATCH_MAX)
+var pdfBatchMax = envInt("PDF_BATCH_MAX", 200)
+
+var errExportTooLarge = errors.New("too many judgments for one pdf (max " + strconv.Itoa(pdfBatchMax) + "); narrow the filter")
+
+func pdfOptions() brief.Options {
+	return brief.Options{
+		Heading: getEnv("PDF_HEADING", "บันทึกย่อคำพิพากษา"),
+		Printed: time.Now(),
+	}
+}
+
+// judgmentBrief แปลง judgment เป็นเนื้อหาของ case brief
+func judgmentBrief(j Judgment) brief.Brief {
+	b := brief.Brief{Title: j.Title, Ref: deref(j.DocNo)}
+	for _, m := range judgmentMeta(j) {
+		b.Meta = append(b.Meta, brief.Field{Label: m.Label, Text: m.Text})
+	}
+	for _, s := range judgmentBody(j) {
+		b.Sections = append(b.Sections, brief.Field{Label: s.Label, Text: strings.TrimSpace(s.Text)})
+	}
+	return b
+}
+
+// judgmentPDF: GET /api/judgments/:id.pdf (เรียกจาก getJudgment)
+func judgmentPDF(c *gin.Context, pool *pgxpool.Pool, id string) {
+	exportJudgmentAs(c, pool, id, exportFormats["pdf"])
+}
+
+// pdfExporter เก็บทุกเรื่องไว้ก่อน แล้วสร้าง PDF ตอน Close
+type pdfExporter struct {
+	w      io.Writer
+	briefs []brief.Brief
+}
+
+func newPDFExporter(w io.Writer) (judgmentExporter, error) {
+	// ตรวจฟอนต์ก่อนเริ่มเขียน จะได้ตอบ error เป็น JSON ได้
+	if err := brief.Available(); err != nil {
+		return nil, err
+	}
+	return &pdfExporter{w: w}, nil
+}
+
+func (e *pdfExporter) Write(j Judgment) error {
+	if len(e.briefs) >= pdfBatchMax {
+		return errExportTooLarge
+	}
+	e.briefs = append(e.briefs, judgmentBrief(j))
+	return nil
+}
+
+func (e *pdfExporter) Close() error {
+	return brief.Render(e.w, pdfOptions(), e.briefs...)
+}
@@ -0,0 +1,329 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JudgmentRevision struct {
+	Rev           int                  `json:"rev"`
+	Action        string               `json:"action"` // create/update/delete/restore
+	ChangedBy     *string              `json:"changed_by"`
+	ChangedByName *string              `json:"changed_by_name"`
+	ChangedAt     time.Time            `json:"changed_at"`
+	Snapshot      *createUpdatePayload `json:"snapshot,omitempty"`
+}
+
+type RevisionChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type RevisionDiff struct {
+	From    int              `json:"from"`
+	To      int              `json:"to"`
+	Changes []RevisionChange `json:"changes"`
+}
+
+// snapshotSQL สร้าง jsonb จากแถว judgments (alias j) ให้ key ตรงกับ createUpdatePayload
+const snapshotSQL = `jsonb_build_object(
+  'title', j.title,
+  'case_no', j.case_no,
+  'court', j.court,
+  'category', j.category,
+  'judgment_date', to_char(j.judgment_date,'YYYY-MM-DD'),
+  'parties', j.parties,
+  'facts', j.facts,
+  'issues', j.issues,
+  'holding', j.holding,
+  'notes', j.notes,
+  'tags', to_jsonb(j.tags)
+)`
+
+// ลำดับ field ตอนแสดง diff
+var snapshotFields = []string{
+	"title", "case_no", "court", "category", "judgment_date", "parties",
+	"facts", "issues", "holding", "notes", "tags",
+}
+
+func registerJudgmentRevisionRoutes(auth *gin.RouterGroup, pool *pgxpool.Pool) {
+	auth.GET("/judgments/:id/revisions", func(c *gin.Context) { listRevisions(c, pool) })
+	auth.GET("/judgments/:id/revisions/:rev", func(c *gin.Context) { getRevision(c, pool) })
+	auth.GET("/judgments/:id/revisions/:rev/diff", func(c *gin.Context) { diffRevisions(c, pool) })
+	auth.POST("/judgments/:id/revisions/:rev/restore", func(c *gin.Context) { restoreRevision(c, pool) })
+}
+
+// recordRevision เก็บ snapshot ปัจจุบันของ judgment เป็น revision ถัดไป
+// ต้องเรียกใน tx เดียวกับที่แก้ข้อมูล (แถว judgment ถูกล็อกไว้แล้ว เลข rev จึงไม่ชนกัน)
+func recordRevision(ctx context.Context, tx pgx.Tx, id, action, userID string) error {
+	q := `
+INSERT INTO judgment_revisions (judgment_id, rev, action, snapshot, changed_by)
+SELECT j.id,
+       COALESCE((SELECT max(r.rev) FROM judgment_revisions r WHERE r.judgment_id = j.id), 0) + 1,
+       $2, ` + snapshotSQL + `, $3
+FROM judgments j
+WHERE j.id = $1`
+
+	ct, err := tx.Exec(ctx, q, id, action, nullIfEmpty(userID))
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.New("judgment not found while recording revision")
+	}
+	return nil
+}
+
+// recordRevisions เหมือน recordRevision แต่ทีละหลายแถว (ใช้กับการแก้แบบ bulk เช่นเปลี่ยนชื่อศาล/tag)
+func recordRevisions(ctx context.Context, tx pgx.Tx, ids []string, action, userID string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+INSERT INTO judgment_revisions (judgment_id, rev, action, snapshot, changed_by)
+SELECT j.id,
+       COALESCE((SELECT max(r.rev) FROM judgment_revisions r WHERE r.judgment_id = j.id), 0) + 1,
+       $2, `+snapshotSQL+`, $3
+FROM judgments j
+WHERE j.id::text = ANY($1)`, ids, action, nullIfEmpty(userID))
+	return err
+}
+
+func listRevisions(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+
+	rows, err := pool.Query(c, `
+		SELECT r.rev, r.action, r.changed_by, u.name, r.changed_at
+		FROM judgment_revisions r
+		LEFT JOIN users u ON u.id = r.changed_by
+		WHERE r.judgment_id = $1
+		ORDER BY r.rev DESC
+	`, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	out := make([]JudgmentRevision, 0)
+	for rows.Next() {
+		var r JudgmentRevision
+		if err := rows.Scan(&r.Rev, &r.Action, &r.ChangedBy, &r.ChangedByName, &r.ChangedAt); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if len(out) == 0 {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(200, out)
+}
+
+func getRevision(c *gin.Context, pool *pgxpool.Pool) {
+	rev, ok := revParam(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+
+	r, err := loadRevision(c, pool, c.Param("id"), rev)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "revision not found"})
+		return
+	}
+	if r.Snapshot != nil {
+		r.Snapshot.JudgmentDate = localDate(r.Snapshot.JudgmentDate, cal)
+	}
+	c.JSON(200, r)
+}
+
+// diffRevisions เทียบ :rev กับ ?against= (ค่าเริ่มต้นคือ revision ก่อนหน้า)
+func diffRevisions(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	to, ok := revParam(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+	from := to - 1
+	if s := c.Query("against"); s != "" {
+		if from, ok = revParam(c, s); !ok {
+			return
+		}
+	}
+
+	toRev, err := loadRevision(c, pool, id, to)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "revision not found"})
+		return
+	}
+
+	// rev แรกเทียบกับความว่างเปล่า = ทุก field ถูกเพิ่มเข้ามา
+	var fromSnap *createUpdatePayload
+	if from > 0 {
+		fromRev, err := loadRevision(c, pool, id, from)
+		if err != nil {
+			c.JSON(404, gin.H{"error": "revision not found"})
+			return
+		}
+		fromSnap = fromRev.Snapshot
+	}
+
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	for _, s := range []*createUpdatePayload{fromSnap, toRev.Snapshot} {
+		if s != nil {
+			s.JudgmentDate = localDate(s.JudgmentDate, cal)
+		}
+	}
+
+	changes, err := diffSnapshots(fromSnap, toRev.Snapshot)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, RevisionDiff{From: from, To: to, Changes: changes})
+}
+
+func restoreRevision(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	rev, ok := revParam(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	if !lockJudgmentForWrite(c, tx, id) {
+		return
+	}
+
+	r, err := loadRevision(c, tx, id, rev)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "revision not found"})
+		return
+	}
+	if err := writeJudgment(c, tx, id, *r.Snapshot, "restore"); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	getJudgment(c, pool)
+}
+
+func loadRevision(ctx context.Context, q querier, id string, rev int) (JudgmentRevision, error) {
+	var r JudgmentRevision
+	var raw []byte
+	err := q.QueryRow(ctx, `
+		SELECT r.rev, r.action, r.changed_by, u.name, r.changed_at, r.snapshot
+		FROM judgment_revisions r
+		LEFT JOIN users u ON u.id = r.changed_by
+		WHERE r.judgment_id = $1 AND r.rev = $2
+	`, id, rev).Scan(&r.Rev, &r.Action, &r.ChangedBy, &r.ChangedByName, &r.ChangedAt, &raw)
+	if err != nil {
+		return r, err
+	}
+	r.Snapshot = &createUpdatePayload{}
+	if err := json.Unmarshal(raw, r.Snapshot); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// diffSnapshots คืนเฉพาะ field ที่ค่าต่างกัน เรียงตาม snapshotFields
+func diffSnapshots(from, to *createUpdatePayload) ([]RevisionChange, error) {
+	a, err := snapshotMap(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := snapshotMap(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]RevisionChange, 0)
+	for _, f := range orderedSnapshotKeys(a, b) {
+		if !reflect.DeepEqual(a[f], b[f]) {
+			changes = append(changes, RevisionChange{Field: f, From: a[f], To: b[f]})
+		}
+	}
+	return changes, nil
+}
+
+func snapshotMap(s *createUpdatePayload) (map[string]any, error) {
+	m := map[string]any{}
+	if s == nil {
+		return m, nil
+	}
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	// tags ว่างกับ null ถือว่าเหมือนกัน
+	if m["tags"] == nil {
+		m["tags"] = []any{}
+	}
+	return m, nil
+}
+
+func orderedSnapshotKeys(maps ...map[string]any) []string {
+	seen := map[string]bool{}
+	keys := make([]string, 0, len(snapshotFields))
+	for _, f := range snapshotFields {
+		seen[f] = true
+		keys = append(keys, f)
+	}
+	extra := []string{}
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				extra = append(extra, k)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(keys, extra...)
+}
+
+func revParam(c *gin.Context, s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		c.JSON(400, gin.H{"error": "invalid revision number"})
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,221 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/legal"
+	"judgment-notes/cmd/internal/search"
+)
+
+// judgmentIndexVersion: เพิ่มเลขนี้เมื่อเปลี่ยนวิธีสร้างข้อมูลที่ derive จาก judgment
+// (search_vector, เลขคดีที่แยกแล้ว, คำพ้องของ tag, citation และมาตราที่อ้างอัตโนมัติ ฯลฯ) แถวเก่าจะถูก reindex โดย StartReindexer
+const judgmentIndexVersion = 7
+
+// ความยาว snippet (ตัวอักษร) ที่แสดงใน highlights
+const snippetRunes = 160
+
+// reindexJudgment แยกเลขคดี และสร้าง search_vector, citation และมาตราที่อ้างใหม่จากข้อมูลปัจจุบันของ judgment
+// เรียกใน tx เดียวกับที่เขียนข้อมูล
+func reindexJudgment(ctx context.Context, q querier, id string) error {
+	var title string
+	var docNo, caseNo, court, parties, facts, issues, holding, notes *string
+	var tags []string
+	err := q.QueryRow(ctx, `
+		SELECT doc_no, title, case_no, court, parties, facts, issues, holding, notes, tags
+		FROM judgments WHERE id=$1
+	`, id).Scan(&docNo, &title, &caseNo, &court, &parties, &facts, &issues, &holding, &notes, &tags)
+	if err != nil {
+		return err
+	}
+
+	// เลขคดี: เก็บส่วนประกอบ หรือเหตุผลที่แยกไม่ได้
+	var caseType, casePrefix, caseErr *string
+	var caseNumber, caseYear *int
+	var caseVariants []string
+	if strings.TrimSpace(deref(caseNo)) != "" {
+		if n, err := legal.ParseCaseNo(*caseNo); err != nil {
+			msg := err.Error()
+			caseErr = &msg
+		} else {
+			caseType, casePrefix, caseNumber, caseYear = &n.Type, &n.Prefix, &n.Number, &n.Year
+			caseVariants = n.Variants() // ค้นด้วยรูปแบบไหนก็เจอ (1234/65, ฎ.1234/2565 ...)
+		}
+	}
+
+	// ชื่ออังกฤษและคำพ้องของ tag: ค้นด้วยคำไหนก็เจอ
+	var tagLabels string
+	err = q.QueryRow(ctx, `
+		SELECT COALESCE(string_agg(concat_ws(' ', t.name_en,
+		         (SELECT string_agg(s.synonym, ' ') FROM tag_synonyms s WHERE s.tag_id = t.id)), ' '), '')
+		FROM tags t WHERE t.name_th = ANY($1::text[])
+	`, tags).Scan(&tagLabels)
+	if err != nil {
+		return err
+	}
+
+	vec := search.Vector(
+		search.Field{Text: title, Weight: search.WeightA},
+		search.Field{Text: deref(docNo), Weight: search.WeightA},
+		search.Field{Text: deref(caseNo), Weight: search.WeightA},
+		search.Field{Text: strings.Join(caseVariants, " "), Weight: search.WeightA},
+		search.Field{Text: deref(court), Weight: search.WeightB},
+		search.Field{Text: deref(parties), Weight: search.WeightB},
+		search.Field{Text: strings.Join(tags, " "), Weight: search.WeightB},
+		search.Field{Text: tagLabels, Weight: search.WeightC},
+		search.Field{Text: deref(issues), Weight: search.WeightC},
+		search.Field{Text: deref(holding), Weight: search.WeightC},
+		search.Field{Text: deref(facts), Weight: search.WeightD},
+		search.Field{Text: deref(notes), Weight: search.WeightD},
+	)
+
+	_, err = q.Exec(ctx, `
+		UPDATE judgments
+		SET search_vector=$2::tsvector, index_version=$3,
+		    case_type=$4, case_prefix=$5, case_number=$6, case_year=$7, case_no_error=$8
+		WHERE id=$1`,
+		id, vec, judgmentIndexVersion, caseType, casePrefix, caseNumber, caseYear, caseErr)
+	if err != nil {
+		return err
+	}
+
+	if err := syncAutoCitations(ctx, q, id, caseNo,
+		title, deref(facts), deref(issues), deref(holding), deref(notes)); err != nil {
+		return err
+	}
+	if err := syncStatuteRefs(ctx, q, id,
+		title, deref(issues), deref(holding), deref(facts), deref(notes)); err != nil {
+		return err
+	}
+	return linkCitingJudgments(ctx, q, id, caseNo)
+}
+
+// reindexSignal ปลุก worker ให้ reindex แถวที่ index_version เก่า (buffer 1: ขอซ้ำระหว่างทำงานรวมเป็นรอบเดียว)
+var reindexSignal = make(chan struct{}, 1)
+
+// requestReindex ขอให้ worker reindex รอบถัดไป (ไม่ block) ใช้หลังแก้ judgment แบบ bulk
+func requestReindex() {
+	select {
+	case reindexSignal <- struct{}{}:
+	default:
+	}
+}
+
+// StartReindexer รัน worker ตัวเดียวที่ reindex แถวที่ index_version เก่ากว่าปัจจุบัน
+// (รวมข้อมูลก่อนมีระบบค้นหา) ตอน start server และทุกครั้งที่มี requestReindex
+func StartReindexer(ctx context.Context, pool *pgxpool.Pool) {
+	requestReindex()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reindexSignal:
+			}
+			n, failed := reindexStale(ctx, pool)
+			if n > 0 || failed > 0 {
+				log.Printf("reindex: %d judgment(s) updated, %d failed", n, failed)
+			}
+		}
+	}()
+}
+
+// reindexStale ไล่ reindex ทีละแถว แต่ละแถวใน tx ของตัวเองและล็อกแถวไว้ (กันชนกับการแก้ของ user)
+// แถวที่ error ข้ามไปก่อน จะถูกลองใหม่ในรอบถัดไป
+func reindexStale(ctx context.Context, pool *pgxpool.Pool) (done, failed int) {
+	last := ""
+	for {
+		rows, err := pool.Query(ctx, `
+SELECT id::text FROM judgments
+WHERE index_version < $1 AND id::text > $2
+ORDER BY id::text LIMIT 200`, judgmentIndexVersion, last)
+		if err != nil {
+			log.Printf("reindex: %v", err)
+			return done, failed
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			log.Printf("reindex: %v", err)
+			return done, failed
+		}
+		if len(ids) == 0 {
+			return done, failed
+		}
+		for _, id := range ids {
+			last = id
+			ok, err := reindexStaleJudgment(ctx, pool, id)
+			if err != nil {
+				log.Printf("reindex %s: %v", id, err)
+				failed++
+				continue
+			}
+			if ok {
+				done++
+			}
+		}
+	}
+}
+
+// reindexStaleJudgment คืน false ถ้าแถวถูก index แล้ว (เช่น user แก้ระหว่างนั้น) หรือถูกลบไป
+func reindexStaleJudgment(ctx context.Context, pool *pgxpool.Pool, id string) (bool, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var stale bool
+	err = tx.QueryRow(ctx, `
+SELECT index_version < $2 FROM judgments WHERE id::text = $1 FOR UPDATE`, id, judgmentIndexVersion).Scan(&stale)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil || !stale {
+		return false, err
+	}
+	if err := reindexJudgment(ctx, tx, id); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// judgmentHighlights ทำ snippet ของแต่ละ field ที่มีคำค้น
+func judgmentHighlights(j Judgment, terms []string) map[string]string {
+	fields := []struct {
+		name string
+		text *string
+	}{
+		{"title", &j.Title},
+		{"doc_no", j.DocNo},
+		{"case_no", j.CaseNo},
+		{"court", j.Court},
+		{"parties", j.Parties},
+		{"facts", j.Facts},
+		{"issues", j.Issues},
+		{"holding", j.Holding},
+		{"notes", j.Notes},
+	}
+
+	out := map[string]string{}
+	for _, f := range fields {
+		if snippet, ok := search.Highlight(deref(f.text), terms, snippetRunes); ok {
+			out[f.name] = snippet
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,142 @@
+package httpapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sortColumn คือ expression ที่ใช้เรียง + type สำหรับ cast ค่าจาก cursor กลับ
+type sortColumn struct {
+	Expr string
+	Type string
+}
+
+// judgmentSort: การเรียงที่ client เลือก (?sort=&order=) ทุกแบบปิดท้ายด้วย id
+// เพื่อให้ลำดับแน่นอนและใช้ทำ keyset pagination ได้
+type judgmentSort struct {
+	Key  string
+	Desc bool
+	Cols []sortColumn
+}
+
+var defaultSortOrderDesc = map[string]bool{
+	"date":      true,
+	"updated":   true,
+	"doc_no":    false,
+	"title":     false,
+	"relevance": true,
+}
+
+func parseJudgmentSort(c *gin.Context, jq judgmentQuery) (judgmentSort, error) {
+	key := strings.ToLower(strings.TrimSpace(c.Query("sort")))
+	if key == "" {
+		key = "date"
+		if jq.Terms != nil {
+			key = "relevance"
+		}
+	}
+	desc, ok := defaultSortOrderDesc[key]
+	if !ok {
+		return judgmentSort{}, errors.New("sort must be one of date, updated, doc_no, title, relevance")
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Query("order"))) {
+	case "":
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	default:
+		return judgmentSort{}, errors.New("order must be asc or desc")
+	}
+
+	s := judgmentSort{Key: key, Desc: desc}
+	// วันที่ว่างอยู่ท้ายเสมอ (NULLS LAST) ทั้งสองทิศ
+	dateExpr := "COALESCE(judgment_date, '-infinity'::date)"
+	if !desc {
+		dateExpr = "COALESCE(judgment_date, 'infinity'::date)"
+	}
+	switch key {
+	case "date":
+		s.Cols = []sortColumn{{dateExpr, "date"}, {"updated_at", "timestamptz"}}
+	case "updated":
+		s.Cols = []sortColumn{{"updated_at", "timestamptz"}}
+	case "doc_no":
+		s.Cols = []sortColumn{{"COALESCE(doc_no, '')", "text"}}
+	case "title":
+		s.Cols = []sortColumn{{"title", "text"}}
+	case "relevance":
+		if jq.Terms == nil {
+			return judgmentSort{}, errors.New("sort=relevance requires search")
+		}
+		s.Cols = []sortColumn{{jq.RankExpr, "float8"}, {dateExpr, "date"}}
+	}
+	s.Cols = append(s.Cols, sortColumn{"id", "uuid"})
+	return s, nil
+}
+
+func (s judgmentSort) orderBy() string {
+	dir := " ASC"
+	if s.Desc {
+		dir = " DESC"
+	}
+	parts := make([]string, len(s.Cols))
+	for i, col := range s.Cols {
+		parts[i] = col.Expr + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// selectKeys คือ column ต่อท้าย SELECT ไว้สร้าง cursor จากแถวสุดท้าย
+func (s judgmentSort) selectKeys() string {
+	parts := make([]string, len(s.Cols))
+	for i, col := range s.Cols {
+		parts[i] = "(" + col.Expr + ")::text"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// after คือเงื่อนไข keyset: แถวที่อยู่ถัดจาก cursor ตามลำดับเดียวกัน
+func (s judgmentSort) after(cur judgmentCursor, args *sqlArgs) string {
+	lhs := make([]string, len(s.Cols))
+	rhs := make([]string, len(s.Cols))
+	for i, col := range s.Cols {
+		lhs[i] = col.Expr
+		rhs[i] = args.add(cur.Values[i]) + "::" + col.Type
+	}
+	op := " > "
+	if s.Desc {
+		op = " < "
+	}
+	return "(" + strings.Join(lhs, ", ") + ")" + op + "(" + strings.Join(rhs, ", ") + ")"
+}
+
+// judgmentCursor เข้ารหัสเป็น base64 ส่งให้ client แบบ opaque
+type judgmentCursor struct {
+	Key    string   `json:"k"`
+	Desc   bool     `json:"d"`
+	Values []string `json:"v"`
+}
+
+func (cur judgmentCursor) encode() string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeJudgmentCursor(s string, sort judgmentSort) (judgmentCursor, error) {
+	var cur judgmentCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(raw, &cur)
+	}
+	if err != nil {
+		return cur, errors.New("invalid cursor")
+	}
+	if cur.Key != sort.Key || cur.Desc != sort.Desc || len(cur.Values) != len(sort.Cols) {
+		return cur, errors.New("cursor does not match sort order")
+	}
+	return cur, nil
+}
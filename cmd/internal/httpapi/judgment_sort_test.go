@@ -0,0 +1,110 @@
+package httpapi
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func sortFromQuery(t *testing.T, query string, jq judgmentQuery) (judgmentSort, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/judgments?"+query, nil)
+	return parseJudgmentSort(c, jq)
+}
+
+func TestParseJudgmentSort(t *testing.T) {
+	search := judgmentQuery{RankExpr: "rank", Terms: []string{"x"}}
+	tests := []struct {
+		query    string
+		jq       judgmentQuery
+		wantKey  string
+		wantDesc bool
+		wantCols int
+		wantErr  bool
+	}{
+		{"", judgmentQuery{}, "date", true, 3, false},
+		{"", search, "relevance", true, 3, false},
+		{"sort=title", judgmentQuery{}, "title", false, 2, false},
+		{"sort=TITLE&order=desc", judgmentQuery{}, "title", true, 2, false},
+		{"sort=updated&order=asc", judgmentQuery{}, "updated", false, 2, false},
+		{"sort=doc_no", judgmentQuery{}, "doc_no", false, 2, false},
+		{"sort=relevance", judgmentQuery{}, "", false, 0, true},
+		{"sort=author", judgmentQuery{}, "", false, 0, true},
+		{"sort=date&order=up", judgmentQuery{}, "", false, 0, true},
+	}
+	for _, tt := range tests {
+		s, err := sortFromQuery(t, tt.query, tt.jq)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error", tt.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.query, err)
+			continue
+		}
+		if s.Key != tt.wantKey || s.Desc != tt.wantDesc || len(s.Cols) != tt.wantCols {
+			t.Errorf("%q = %s desc=%v cols=%d; want %s desc=%v cols=%d",
+				tt.query, s.Key, s.Desc, len(s.Cols), tt.wantKey, tt.wantDesc, tt.wantCols)
+		}
+		if last := s.Cols[len(s.Cols)-1]; last.Expr != "id" {
+			t.Errorf("%q: last sort column = %s, want id tiebreaker", tt.query, last.Expr)
+		}
+	}
+}
+
+func TestJudgmentCursorRoundTrip(t *testing.T) {
+	s, err := sortFromQuery(t, "sort=date", judgmentQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur := judgmentCursor{Key: s.Key, Desc: s.Desc, Values: []string{"2024-03-15", "2024-03-16 10:00:00+07", "0b4c2f0e-6f4e-4c7a-9a51-0d7b6d1f2b11"}}
+	got, err := decodeJudgmentCursor(cur.encode(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, cur) {
+		t.Errorf("decode = %+v, want %+v", got, cur)
+	}
+
+	var args sqlArgs
+	where := s.after(got, &args)
+	want := "(COALESCE(judgment_date, '-infinity'::date), updated_at, id) < ($1::date, $2::timestamptz, $3::uuid)"
+	if where != want {
+		t.Errorf("after = %s\nwant  %s", where, want)
+	}
+	if len(args) != 3 || args[0] != "2024-03-15" {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestDecodeJudgmentCursorInvalid(t *testing.T) {
+	s, err := sortFromQuery(t, "sort=title", judgmentQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	tests := []struct {
+		name, cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"k":"title"}`))},
+		{"not json", enc("title|abc")},
+		{"wrong value type", enc(`{"k":"title","d":false,"v":[1,2]}`)},
+		{"other sort key", judgmentCursor{Key: "date", Values: []string{"a", "b"}}.encode()},
+		{"other direction", judgmentCursor{Key: "title", Desc: true, Values: []string{"a", "b"}}.encode()},
+		{"too few values", judgmentCursor{Key: "title", Values: []string{"a"}}.encode()},
+	}
+	for _, tt := range tests {
+		if _, err := decodeJudgmentCursor(tt.cursor, s); err == nil {
+			t.Errorf("%s: expected error for cursor %q", tt.name, tt.cursor)
+		}
+	}
+}
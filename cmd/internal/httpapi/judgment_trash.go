@@ -0,0 +1,192 @@
+package httpapi
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/storage"
+)
+
+// registerJudgmentTrashRoutes: ถังขยะของ judgments (admin เท่านั้น)
+func registerJudgmentTrashRoutes(admin *gin.RouterGroup, pool *pgxpool.Pool, store storage.Store) {
+	admin.GET("/trash/judgments", func(c *gin.Context) { listTrash(c, pool) })
+	admin.POST("/trash/judgments/:id/restore", func(c *gin.Context) { restoreFromTrash(c, pool) })
+	admin.DELETE("/trash/judgments/:id", func(c *gin.Context) { purgeFromTrash(c, pool, store) })
+}
+
+func listTrash(c *gin.Context, pool *pgxpool.Pool) {
+	cal, ok := calendarParam(c)
+	if !ok {
+		return
+	}
+	page, limit, offset := paginationParams(c)
+
+	var total int
+	if err := pool.QueryRow(c, `SELECT COUNT(*) FROM judgments WHERE deleted_at IS NOT NULL`).Scan(&total); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := pool.Query(c, `
+SELECT `+judgmentColumns+`
+FROM judgments
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	items := make([]Judgment, 0)
+	for rows.Next() {
+		j, err := scanJudgment(rows)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		j.localize(cal)
+		items = append(items, j)
+	}
+
+	c.JSON(200, PaginatedResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPagesFor(total, limit),
+	})
+}
+
+func restoreFromTrash(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	userID := c.GetString("userID")
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	ct, err := tx.Exec(c, `
+UPDATE judgments
+SET deleted_at=NULL, deleted_by=NULL, updated_by=$2, updated_at=now(), version=version+1
+WHERE id=$1 AND deleted_at IS NOT NULL`, id, nullIfEmpty(userID))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if ct.RowsAffected() == 0 {
+		c.JSON(404, gin.H{"error": "not found in trash"})
+		return
+	}
+	if err := recordRevision(c, tx, id, "restore", userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	getJudgment(c, pool)
+}
+
+// purgeFromTrash ลบถาวร (รวมประวัติ revision และไฟล์แนบ) ได้เฉพาะรายการที่อยู่ในถังขยะแล้ว
+func purgeFromTrash(c *gin.Context, pool *pgxpool.Pool, store storage.Store) {
+	found, err := purgeJudgment(c, pool, store, c.Param("id"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(404, gin.H{"error": "not found in trash"})
+		return
+	}
+	c.Status(204)
+}
+
+// purgeJudgment ลบ judgment หนึ่งรายการในถังขยะ แล้วลบไฟล์แนบออกจาก store
+// ต้องลบ attachment ใน statement เดียวกับ judgment (แบบ purgeExpiredTrash)
+// ถ้าลบ judgment ก่อน ON DELETE CASCADE จะลบแถว attachment ไปโดยไม่ได้ storage_key มา
+func purgeJudgment(ctx context.Context, q querier, store storage.Store, id string) (bool, error) {
+	var found bool
+	var keys []string
+	err := q.QueryRow(ctx, `
+WITH purged AS (
+  DELETE FROM judgments WHERE id::text = $1 AND deleted_at IS NOT NULL
+  RETURNING id
+), revs AS (
+  DELETE FROM judgment_revisions r USING purged p WHERE r.judgment_id = p.id
+), atts AS (
+  DELETE FROM judgment_attachments a USING purged p WHERE a.judgment_id = p.id RETURNING a.storage_key
+)
+SELECT EXISTS (SELECT 1 FROM purged), COALESCE((SELECT array_agg(storage_key) FROM atts), '{}')`, id).Scan(&found, &keys)
+	if err != nil {
+		return false, err
+	}
+	deleteStoredFiles(ctx, store, keys)
+	return found, nil
+}
+
+// purgeExpiredTrash ลบถาวรทุกรายการที่อยู่ในถังขยะนานกว่า days วัน
+func purgeExpiredTrash(ctx context.Context, pool *pgxpool.Pool, store storage.Store, days int) (int, error) {
+	var n int
+	var keys []string
+	err := pool.QueryRow(ctx, `
+WITH purged AS (
+  DELETE FROM judgments
+  WHERE deleted_at IS NOT NULL AND deleted_at < now() - make_interval(days => $1)
+  RETURNING id
+), revs AS (
+  DELETE FROM judgment_revisions r USING purged p WHERE r.judgment_id = p.id
+), atts AS (
+  DELETE FROM judgment_attachments a USING purged p WHERE a.judgment_id = p.id RETURNING a.storage_key
+)
+SELECT (SELECT COUNT(*) FROM purged), COALESCE((SELECT array_agg(storage_key) FROM atts), '{}')`, days).Scan(&n, &keys)
+	if err != nil {
+		return 0, err
+	}
+	deleteStoredFiles(ctx, store, keys)
+	return n, nil
+}
+
+// StartTrashPurger รัน purge เป็นระยะตาม TRASH_RETENTION_DAYS (ค่าเริ่มต้น 30 วัน, 0 = ปิด)
+// และ TRASH_PURGE_INTERVAL (duration เช่น 1h)
+func StartTrashPurger(ctx context.Context, pool *pgxpool.Pool, store storage.Store) {
+	days, err := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	if err != nil || days <= 0 {
+		log.Println("trash purge: disabled")
+		return
+	}
+	interval, err := time.ParseDuration(getEnv("TRASH_PURGE_INTERVAL", "1h"))
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			n, err := purgeExpiredTrash(ctx, pool, store, days)
+			if err != nil {
+				log.Printf("trash purge: %v", err)
+			} else if n > 0 {
+				log.Printf("trash purge: removed %d judgment(s) older than %d days", n, days)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
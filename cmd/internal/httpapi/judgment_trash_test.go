@@ -0,0 +1,155 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/storage"
+)
+
+// fakeRow / rowQuerier: querier ที่ตอบ QueryRow ด้วยค่าที่กำหนด (ไว้ทดสอบโดยไม่ต้องมี DB)
+type fakeRow struct {
+	vals []any
+	err  error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i := range dest {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(r.vals[i]))
+	}
+	return nil
+}
+
+type rowQuerier struct {
+	row  fakeRow
+	sqls []string
+}
+
+func (q *rowQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected Exec")
+}
+
+func (q *rowQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, errors.New("unexpected Query")
+}
+
+func (q *rowQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	q.sqls = append(q.sqls, sql)
+	return q.row
+}
+
+func putTestFile(t *testing.T, store storage.Store, key string) {
+	t.Helper()
+	if err := store.Put(context.Background(), key, strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertStoreMissing(t *testing.T, store storage.Store, key string) {
+	t.Helper()
+	f, err := store.Open(context.Background(), key)
+	if err == nil {
+		f.Close()
+		t.Errorf("file %s still in storage after purge", key)
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("open %s: %v", key, err)
+	}
+}
+
+func TestPurgeJudgmentDeletesFiles(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	putTestFile(t, store, "attachments/j1/a.pdf")
+	putTestFile(t, store, "attachments/j2/keep.pdf")
+
+	q := &rowQuerier{row: fakeRow{vals: []any{true, []string{"attachments/j1/a.pdf"}}}}
+	found, err := purgeJudgment(context.Background(), q, store, "j1")
+	if err != nil || !found {
+		t.Fatalf("purgeJudgment = %v, %v", found, err)
+	}
+	assertStoreMissing(t, store, "attachments/j1/a.pdf")
+	if f, err := store.Open(context.Background(), "attachments/j2/keep.pdf"); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	} else {
+		f.Close()
+	}
+
+	// ต้องได้ storage_key จาก statement เดียวกับที่ลบ judgment (ก่อน cascade)
+	if len(q.sqls) != 1 || !strings.Contains(q.sqls[0], "DELETE FROM judgment_attachments") {
+		t.Errorf("attachments must be deleted in the same statement as the judgment: %q", q.sqls)
+	}
+}
+
+func TestPurgeJudgmentNotFound(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := &rowQuerier{row: fakeRow{vals: []any{false, []string{}}}}
+	if found, err := purgeJudgment(context.Background(), q, store, "missing"); err != nil || found {
+		t.Errorf("purgeJudgment = %v, %v; want false, nil", found, err)
+	}
+}
+
+// TestPurgeJudgmentDB รันกับ Postgres จริงเมื่อตั้ง TEST_DATABASE_URL (ฐานข้อมูลทิ้งได้ จะ migrate ให้)
+func TestPurgeJudgmentDB(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	m, err := migrate.New("file://../../../migrations", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	if err := pool.QueryRow(ctx, `INSERT INTO judgments (title, deleted_at) VALUES ('purge test', now()) RETURNING id::text`).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	key := "attachments/" + id + "/a.txt"
+	putTestFile(t, store, key)
+	if _, err := pool.Exec(ctx, `
+INSERT INTO judgment_attachments (judgment_id, filename, content_type, size_bytes, sha256, storage_key)
+VALUES ($1, 'a.txt', 'text/plain', 5, 'x', $2)`, id, key); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := purgeJudgment(ctx, pool, store, id)
+	if err != nil || !found {
+		t.Fatalf("purgeJudgment = %v, %v", found, err)
+	}
+	assertStoreMissing(t, store, key)
+	var left int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM judgment_attachments WHERE storage_key = $1`, key).Scan(&left); err != nil || left != 0 {
+		t.Errorf("attachment rows left = %d, %v", left, err)
+	}
+}
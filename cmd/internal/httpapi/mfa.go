@@ -0,0 +1,676 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ชื่อที่แสดงในแอป authenticator
+var mfaIssuer = getEnv("MFA_ISSUER", "Judgment Notes")
+
+// key เข้ารหัส TOTP secret ใน DB (TOTP_ENCRYPTION_KEY ไม่ตั้งใช้ JWT_SECRET)
+var totpKey = sha256.Sum256([]byte(getEnv("TOTP_ENCRYPTION_KEY", string(jwtSecret))))
+
+// อายุ challenge token ระหว่างขั้น password กับขั้นรหัส 2FA
+const mfaChallengeTTL = 5 * time.Minute
+
+// กันเดารหัส: ผิดครบ mfaMaxAttempts ครั้ง challenge ใช้ไม่ได้ (ต้อง login ใหม่)
+// ผิดรวมทุก challenge (และหน้าตั้งค่า 2FA) ครบ mfaUserMaxFailures ภายใน mfaLockWindow ล็อกขั้น 2FA ของ user ชั่วคราว
+const (
+	mfaMaxAttempts     = 5
+	mfaUserMaxFailures = 10
+	mfaLockWindow      = 15 * time.Minute
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789" // ไม่มีตัวที่สับสนกัน (0/o, 1/l/i)
+)
+
+type mfaCodePayload struct {
+	Code         string `json:"code"`          // รหัส 6 หลักจากแอป
+	RecoveryCode string `json:"recovery_code"` // หรือ recovery code (ใช้ได้ครั้งเดียว)
+}
+
+type mfaLoginPayload struct {
+	ChallengeToken string `json:"challenge_token"`
+	mfaCodePayload
+}
+
+// mfaState คือข้อมูล 2FA ของ user ที่ใช้ตรวจรหัส
+type mfaState struct {
+	Secret   *string // เข้ารหัสแล้ว (ดู sealSecret)
+	Enabled  bool
+	LastStep *int64
+	Required bool // role บังคับ 2FA
+}
+
+func registerMFARoutes(api *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.POST("/auth/login/mfa", func(c *gin.Context) { loginMFA(c, pool) })
+
+	mfa := api.Group("/auth/mfa")
+	mfa.Use(mfaSetupMiddleware(pool))
+	mfa.GET("", func(c *gin.Context) { getMFAStatus(c, pool) })
+	mfa.POST("/totp/setup", func(c *gin.Context) { setupTOTP(c, pool) })
+	mfa.POST("/totp/enable", func(c *gin.Context) { enableTOTP(c, pool) })
+	mfa.POST("/totp/disable", func(c *gin.Context) { disableTOTP(c, pool) })
+	mfa.POST("/recovery-codes", func(c *gin.Context) { regenerateRecoveryCodes(c, pool) })
+}
+
+// startMFAChallenge ตอบ challenge token แทน access token เมื่อ user เปิด 2FA ไว้
+func startMFAChallenge(c *gin.Context, pool *pgxpool.Pool, user User, label string) {
+	var cid string
+	if err := pool.QueryRow(c, `
+INSERT INTO mfa_challenges (user_id, expires_at) VALUES ($1, $2) RETURNING id`,
+		user.ID, time.Now().Add(mfaChallengeTTL)).Scan(&cid); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   user.ID,
+		"typ":   "mfa",
+		"cid":   cid,
+		"label": label,
+		"exp":   time.Now().Add(mfaChallengeTTL).Unix(),
+	})
+	s, err := token.SignedString(jwtSecret)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to generate token"})
+		return
+	}
+	c.JSON(200, gin.H{
+		"mfa_required":    true,
+		"challenge_token": s,
+		"expires_in":      int(mfaChallengeTTL.Seconds()),
+		"methods":         []string{"totp", "recovery_code"},
+	})
+}
+
+// loginMFA: POST /auth/login/mfa {"challenge_token": "...", "code": "123456"} (หรือ "recovery_code")
+func loginMFA(c *gin.Context, pool *pgxpool.Pool) {
+	var in mfaLoginPayload
+	if err := c.ShouldBindJSON(&in); err != nil || in.ChallengeToken == "" {
+		c.JSON(400, gin.H{"error": "challenge_token is required"})
+		return
+	}
+	claims, err := parseMFAChallenge(in.ChallengeToken)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "invalid or expired challenge token"})
+		return
+	}
+	userID := fmt.Sprint(claims["sub"])
+	cid, _ := claims["cid"].(string)
+	label, _ := claims["label"].(string)
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	// lock แถว user: การเดารหัสของ user เดียวกันทำทีละ request
+	user, st, err := loadMFAUser(c, tx, userID, true)
+	if err != nil || !st.Enabled {
+		c.JSON(401, gin.H{"error": "invalid or expired challenge token"})
+		return
+	}
+	var failed, recentFailed int
+	err = tx.QueryRow(c, `
+SELECT failed_attempts,
+       (SELECT COALESCE(SUM(failed_attempts), 0) FROM mfa_challenges WHERE user_id = $2 AND created_at > $3)
+FROM mfa_challenges
+WHERE id::text = $1 AND user_id = $2 AND used_at IS NULL AND expires_at > now()`,
+		cid, userID, time.Now().Add(-mfaLockWindow)).Scan(&failed, &recentFailed)
+	if err != nil {
+		c.JSON(401, gin.H{"error": "invalid or expired challenge token"})
+		return
+	}
+	if recentFailed >= mfaUserMaxFailures {
+		c.JSON(429, gin.H{"error": "too many failed attempts, try again later"})
+		return
+	}
+
+	ok, err := checkSecondFactor(c, tx, userID, st, in.mfaCodePayload)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		// ผิดครบแล้วปิด challenge ทันที
+		failed++
+		if _, err := tx.Exec(c, `
+UPDATE mfa_challenges SET failed_attempts = $2, used_at = CASE WHEN $2 >= $3 THEN now() END
+WHERE id = $1`, cid, failed, mfaMaxAttempts); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		if err := tx.Commit(c); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(401, gin.H{"error": "invalid code", "attempts_left": max(mfaMaxAttempts-failed, 0)})
+		return
+	}
+	if _, err := tx.Exec(c, `UPDATE mfa_challenges SET used_at = now() WHERE id = $1`, cid); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	startSession(c, pool, user, label, 200)
+}
+
+func parseMFAChallenge(s string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(s, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != "mfa" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func getMFAStatus(c *gin.Context, pool *pgxpool.Pool) {
+	userID := c.GetString("userID")
+	_, st, err := loadMFAUser(c, pool, userID, false)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "user not found"})
+		return
+	}
+	var left int
+	if err := pool.QueryRow(c, `
+SELECT COUNT(*) FROM user_recovery_codes WHERE user_id=$1 AND used_at IS NULL`, userID).Scan(&left); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"enabled": st.Enabled, "required": st.Required, "recovery_codes_left": left})
+}
+
+// setupTOTP สร้าง secret ใหม่ (ยังไม่เปิดใช้จนกว่าจะยืนยันรหัสที่ /totp/enable)
+func setupTOTP(c *gin.Context, pool *pgxpool.Pool) {
+	userID := c.GetString("userID")
+	user, st, err := loadMFAUser(c, pool, userID, false)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "user not found"})
+		return
+	}
+	if st.Enabled {
+		c.JSON(409, gin.H{"error": "two-factor authentication is already enabled"})
+		return
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: mfaIssuer, AccountName: user.Email})
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	sealed, err := sealSecret(key.Secret())
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	img, err := key.Image(256, 256)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := pool.Exec(c, `
+UPDATE users SET totp_secret=$2, totp_enabled_at=NULL, totp_last_step=NULL WHERE id=$1`, userID, sealed); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{
+		"secret":      key.Secret(),
+		"otpauth_url": key.URL(),
+		"qr_png":      "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()),
+	})
+}
+
+// enableTOTP: ยืนยันรหัสแรกจากแอป แล้วได้ recovery code ชุดแรก (แสดงครั้งเดียว)
+func enableTOTP(c *gin.Context, pool *pgxpool.Pool) {
+	userID := c.GetString("userID")
+	var in mfaCodePayload
+	if err := c.ShouldBindJSON(&in); err != nil || in.Code == "" {
+		c.JSON(400, gin.H{"error": "code is required"})
+		return
+	}
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	_, st, err := loadMFAUser(c, tx, userID, true)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "user not found"})
+		return
+	}
+	if st.Enabled {
+		c.JSON(409, gin.H{"error": "two-factor authentication is already enabled"})
+		return
+	}
+	if st.Secret == nil {
+		c.JSON(400, gin.H{"error": "call /auth/mfa/totp/setup first"})
+		return
+	}
+	if !verifyMFACode(c, tx, userID, st, mfaCodePayload{Code: in.Code}) {
+		return
+	}
+	if _, err := tx.Exec(c, `UPDATE users SET totp_enabled_at=now() WHERE id=$1`, userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	codes, err := replaceRecoveryCodes(c, tx, userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"enabled": true, "recovery_codes": codes})
+}
+
+// disableTOTP: ต้องยืนยันด้วยรหัส 2FA และ role ต้องไม่บังคับ 2FA
+func disableTOTP(c *gin.Context, pool *pgxpool.Pool) {
+	userID := c.GetString("userID")
+	var in mfaCodePayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	_, st, err := loadMFAUser(c, tx, userID, true)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "user not found"})
+		return
+	}
+	if !st.Enabled {
+		c.JSON(409, gin.H{"error": "two-factor authentication is not enabled"})
+		return
+	}
+	if st.Required {
+		c.JSON(403, gin.H{"error": "two-factor authentication is required for your role"})
+		return
+	}
+	if !verifyMFACode(c, tx, userID, st, in) {
+		return
+	}
+	if err := clearMFA(c, tx, userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"enabled": false})
+}
+
+// regenerateRecoveryCodes: ออกชุดใหม่ (ชุดเดิมใช้ไม่ได้อีก) ต้องยืนยันด้วยรหัสจากแอป
+func regenerateRecoveryCodes(c *gin.Context, pool *pgxpool.Pool) {
+	userID := c.GetString("userID")
+	var in mfaCodePayload
+	if err := c.ShouldBindJSON(&in); err != nil || in.Code == "" {
+		c.JSON(400, gin.H{"error": "code is required"})
+		return
+	}
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	_, st, err := loadMFAUser(c, tx, userID, true)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "user not found"})
+		return
+	}
+	if !st.Enabled {
+		c.JSON(409, gin.H{"error": "two-factor authentication is not enabled"})
+		return
+	}
+	if !verifyMFACode(c, tx, userID, st, mfaCodePayload{Code: in.Code}) {
+		return
+	}
+	codes, err := replaceRecoveryCodes(c, tx, userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"recovery_codes": codes})
+}
+
+// adminResetMFA: DELETE /users/:id/mfa ล้าง 2FA (เช่น user ทำโทรศัพท์หาย) และยกเลิกทุก session
+func adminResetMFA(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	var exists bool
+	if err := pool.QueryRow(c, `SELECT EXISTS (SELECT 1 FROM users WHERE id=$1)`, id).Scan(&exists); err != nil || !exists {
+		c.JSON(404, gin.H{"error": "user not found"})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	if err := clearMFA(c, tx, id); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := revokeUserSessions(c, tx, id); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := revokeUserAPIKeys(c, tx, id); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(204)
+}
+
+// forUpdate=true lock แถว user ไว้จนจบ transaction
+func loadMFAUser(ctx context.Context, q querier, userID string, forUpdate bool) (User, mfaState, error) {
+	var u User
+	var st mfaState
+	lock := ""
+	if forUpdate {
+		lock = " FOR UPDATE OF u"
+	}
+	err := q.QueryRow(ctx, `
+SELECT u.id, u.email, u.name, u.role, u.avatar_url, u.totp_enabled_at IS NOT NULL, u.email_verified_at IS NOT NULL, u.created_at,
+       u.totp_secret, u.totp_last_step,
+       EXISTS (SELECT 1 FROM roles r WHERE r.name = u.role AND r.require_mfa)
+FROM users u WHERE u.id = $1`+lock, userID).Scan(
+		&u.ID, &u.Email, &u.Name, &u.Role, &u.AvatarURL, &u.MFAEnabled, &u.EmailVerified, &u.CreatedAt,
+		&st.Secret, &st.LastStep, &st.Required)
+	st.Enabled = u.MFAEnabled
+	return u, st, err
+}
+
+// verifyMFACode ตรวจรหัส 2FA ของหน้าตั้งค่า (เปิด/ปิด 2FA, ออก recovery code ใหม่)
+// ใช้โควตารหัสผิดของ user ร่วมกับขั้น login: ผิดรวมครบ mfaUserMaxFailures ภายใน mfaLockWindow ตอบ 429
+// คืน false เมื่อตอบ error ไปแล้ว; tx ต้อง lock แถว user ไว้ (loadMFAUser forUpdate)
+func verifyMFACode(c *gin.Context, tx pgx.Tx, userID string, st mfaState, in mfaCodePayload) bool {
+	var recentFailed int
+	if err := tx.QueryRow(c, `
+SELECT COALESCE(SUM(failed_attempts), 0) FROM mfa_challenges WHERE user_id = $1 AND created_at > $2`,
+		userID, time.Now().Add(-mfaLockWindow)).Scan(&recentFailed); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	if recentFailed >= mfaUserMaxFailures {
+		c.JSON(429, gin.H{"error": "too many failed attempts, try again later"})
+		return false
+	}
+
+	ok, err := checkSecondFactor(c, tx, userID, st, in)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	if ok {
+		return true
+	}
+	// บันทึกรหัสผิดเป็น challenge ที่ปิดแล้ว ให้นับรวมในหน้าต่างเดียวกับ login
+	if _, err := tx.Exec(c, `
+INSERT INTO mfa_challenges (user_id, failed_attempts, expires_at, used_at) VALUES ($1, 1, now(), now())`,
+		userID); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	c.JSON(400, gin.H{"error": "invalid code", "attempts_left": max(mfaUserMaxFailures-recentFailed-1, 0)})
+	return false
+}
+
+// checkSecondFactor ตรวจรหัสจากแอป (กันใช้รหัสเดิมซ้ำ) หรือ recovery code (ใช้แล้วทิ้ง)
+func checkSecondFactor(ctx context.Context, q querier, userID string, st mfaState, in mfaCodePayload) (bool, error) {
+	if code := strings.TrimSpace(in.RecoveryCode); code != "" {
+		return useRecoveryCode(ctx, q, userID, code)
+	}
+	code := strings.ReplaceAll(strings.TrimSpace(in.Code), " ", "")
+	if !validTOTPCode(code) || st.Secret == nil {
+		return false, nil
+	}
+	secret, err := openSecret(*st.Secret)
+	if err != nil {
+		return false, err
+	}
+	step, ok := matchTOTP(secret, code, st.LastStep, time.Now())
+	if !ok {
+		return false, nil
+	}
+	ct, err := q.Exec(ctx, `
+UPDATE users SET totp_last_step=$2
+WHERE id=$1 AND (totp_last_step IS NULL OR totp_last_step < $2)`, userID, step)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
+
+// matchTOTP รับรหัสของช่วงเวลาปัจจุบัน ±1 ช่วง (นาฬิกาเครื่อง user คลาด) ที่ยังไม่เคยใช้
+func matchTOTP(secret, code string, lastStep *int64, now time.Time) (int64, bool) {
+	for _, skew := range []int{0, -1, 1} {
+		t := now.Add(time.Duration(skew) * 30 * time.Second)
+		step := t.Unix() / 30
+		if lastStep != nil && step <= *lastStep {
+			continue
+		}
+		want, err := totp.GenerateCode(secret, t)
+		if err == nil && subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func useRecoveryCode(ctx context.Context, q querier, userID, code string) (bool, error) {
+	code = normalizeRecoveryCode(code)
+	// รูปแบบผิดไม่ต้องเสียเวลา bcrypt
+	if !validRecoveryCode(code) {
+		return false, nil
+	}
+	rows, err := q.Query(ctx, `
+SELECT id, code_hash FROM user_recovery_codes WHERE user_id=$1 AND used_at IS NULL`, userID)
+	if err != nil {
+		return false, err
+	}
+	var match int64
+	for rows.Next() {
+		var id int64
+		var hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			rows.Close()
+			return false, err
+		}
+		if match == 0 && bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil {
+			match = id
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || match == 0 {
+		return false, err
+	}
+	ct, err := q.Exec(ctx, `UPDATE user_recovery_codes SET used_at=now() WHERE id=$1 AND used_at IS NULL`, match)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() == 1, nil
+}
+
+// replaceRecoveryCodes ลบชุดเดิมแล้วออกชุดใหม่ คืนรหัสจริง (เก็บใน DB เฉพาะ hash)
+func replaceRecoveryCodes(ctx context.Context, q querier, userID string) ([]string, error) {
+	if _, err := q.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(code)), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := q.Exec(ctx, `
+INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1,$2)`, userID, string(hash)); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func clearMFA(ctx context.Context, q querier, userID string) error {
+	if _, err := q.Exec(ctx, `
+UPDATE users SET totp_secret=NULL, totp_enabled_at=NULL, totp_last_step=NULL WHERE id=$1`, userID); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id=$1`, userID)
+	return err
+}
+
+// newRecoveryCode สุ่มรหัสรูปแบบ xxxxx-xxxxx
+func newRecoveryCode() (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < 10; i++ {
+		if i == 5 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+func normalizeRecoveryCode(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer("-", "", " ", "").Replace(s)
+}
+
+// validRecoveryCode: รหัสที่ normalize แล้วต้องยาว 10 ตัวจาก recoveryCodeAlphabet
+func validRecoveryCode(code string) bool {
+	if len(code) != 10 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(recoveryCodeAlphabet, code[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func validTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// sealSecret เข้ารหัส TOTP secret ด้วย AES-GCM ก่อนเก็บ
+func sealSecret(plain string) (string, error) {
+	gcm, err := totpCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plain), nil)), nil
+}
+
+func openSecret(sealed string) (string, error) {
+	gcm, err := totpCipher()
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < gcm.NonceSize() {
+		return "", errors.New("corrupt totp secret")
+	}
+	plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("cannot decrypt totp secret (TOTP_ENCRYPTION_KEY changed?)")
+	}
+	return string(plain), nil
+}
+
+func totpCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(totpKey[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,188 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestMatchTOTP(t *testing.T) {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "a@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := key.Secret()
+	now := time.Unix(1700000025, 0) // กลางช่วงเวลา (step 56666667)
+	step := now.Unix() / 30
+	codeAt := func(d time.Duration) string {
+		c, err := totp.GenerateCode(secret, now.Add(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	last := func(n int64) *int64 { return &n }
+	// รหัสที่ไม่ตรงกับช่วงใดใน ±1
+	wrong := "000000"
+	for _, c := range []string{"111111", "222222"} {
+		if wrong != codeAt(0) && wrong != codeAt(-30*time.Second) && wrong != codeAt(30*time.Second) {
+			break
+		}
+		wrong = c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep *int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current", codeAt(0), nil, step, true},
+		{"previous step (skew -1)", codeAt(-30 * time.Second), nil, step - 1, true},
+		{"next step (skew +1)", codeAt(30 * time.Second), nil, step + 1, true},
+		{"two steps old", codeAt(-60 * time.Second), nil, 0, false},
+		{"two steps ahead", codeAt(60 * time.Second), nil, 0, false},
+		{"replay of current step", codeAt(0), last(step), 0, false},
+		{"older than last used", codeAt(-30 * time.Second), last(step), 0, false},
+		{"after previous use", codeAt(0), last(step - 1), step, true},
+		{"wrong code", wrong, nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchTOTP(secret, tt.code, tt.lastStep, now)
+			if ok != tt.wantOK || got != tt.wantStep {
+				t.Fatalf("matchTOTP = (%d, %v), want (%d, %v)", got, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRecoveryCodeFormat(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !validRecoveryCode(normalizeRecoveryCode(code)) {
+			t.Fatalf("generated code %q is not valid", code)
+		}
+	}
+
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"abcde-fghjk", true},
+		{" ABCDE-FGHJK ", true},
+		{"abcde fghjk", true},
+		{"abcde-fghj", false},   // สั้นไป
+		{"abcde-fghjkm", false}, // ยาวไป
+		{"abcd0-fghjk", false},  // 0 ไม่อยู่ใน alphabet
+		{"abcdi-fghjk", false},  // i ไม่อยู่ใน alphabet
+		{"", false},
+		{"กขคงจ-ฉชซฌญ", false},
+	}
+	for _, tt := range tests {
+		if got := validRecoveryCode(normalizeRecoveryCode(tt.in)); got != tt.want {
+			t.Errorf("validRecoveryCode(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidTOTPCode(t *testing.T) {
+	for in, want := range map[string]bool{
+		"123456":  true,
+		"12345":   false,
+		"1234567": false,
+		"12a456":  false,
+		"๑๒๓๔๕๖":  false,
+		"":        false,
+	} {
+		if got := validTOTPCode(in); got != want {
+			t.Errorf("validTOTPCode(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+// mfaTx จำลอง transaction ของหน้าตั้งค่า 2FA: นับรหัสผิดที่บันทึกไว้ใน mfa_challenges
+type mfaTx struct {
+	pgx.Tx
+	failures  int
+	committed bool
+}
+
+func (tx *mfaTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return fakeRow{vals: []any{tx.failures}}
+}
+
+func (tx *mfaTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "INSERT INTO mfa_challenges"):
+		tx.failures++
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	case strings.Contains(sql, "UPDATE users SET totp_last_step"):
+		return pgconn.NewCommandTag("UPDATE 1"), nil
+	}
+	return pgconn.CommandTag{}, errors.New("unexpected Exec: " + sql)
+}
+
+func (tx *mfaTx) Commit(ctx context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func TestVerifyMFACodeLimitsFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "a@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := sealSecret(key.Secret())
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := mfaState{Secret: &sealed, Enabled: true}
+	verify := func(tx *mfaTx, code string) (bool, int) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/auth/mfa/totp/disable", nil)
+		ok := verifyMFACode(c, tx, "u1", st, mfaCodePayload{Code: code})
+		return ok, w.Code
+	}
+
+	tx := &mfaTx{}
+	for i := 1; i <= mfaUserMaxFailures; i++ {
+		ok, status := verify(tx, "abc")
+		if ok || status != 400 {
+			t.Fatalf("attempt %d = (%v, %d), want (false, 400)", i, ok, status)
+		}
+		if tx.failures != i || !tx.committed {
+			t.Fatalf("attempt %d: failures = %d, committed = %v", i, tx.failures, tx.committed)
+		}
+	}
+
+	// ครบโควตาแล้ว รหัสถูกก็ไม่ตรวจ
+	code, err := totp.GenerateCode(key.Secret(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, status := verify(tx, code); ok || status != 429 {
+		t.Fatalf("locked = (%v, %d), want (false, 429)", ok, status)
+	}
+	if tx.failures != mfaUserMaxFailures {
+		t.Fatalf("a locked attempt was recorded: %d", tx.failures)
+	}
+
+	if ok, _ := verify(&mfaTx{failures: mfaUserMaxFailures - 1}, code); !ok {
+		t.Fatal("a correct code under the limit was rejected")
+	}
+}
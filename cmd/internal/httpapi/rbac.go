@@ -8,19 +8,14 @@ import (
 )
 
 func RequireRole(allowed ...string) gin.HandlerFunc {
-	allow := map[string]bool{}
-	for _, r := range allowed {
-		allow[strings.ToLower(strings.TrimSpace(r))] = true
-	}
-
 	return func(c *gin.Context) {
-		role := strings.ToLower(strings.TrimSpace(c.GetString("userRole")))
+		role := currentRole(c)
 		if role == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 			c.Abort()
 			return
 		}
-		if !allow[role] {
+		if !hasRole(c, allowed...) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			c.Abort()
 			return
@@ -28,3 +23,65 @@ func RequireRole(allowed ...string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// currentRole คืน role ของ user ที่ AuthMiddleware ใส่ไว้ (normalize แล้ว)
+func currentRole(c *gin.Context) string {
+	return strings.ToLower(strings.TrimSpace(c.GetString("userRole")))
+}
+
+// hasRole เช็ค role แบบเดียวกับ RequireRole แต่ใช้ในตัว handler ได้
+func hasRole(c *gin.Context, allowed ...string) bool {
+	role := currentRole(c)
+	if role == "" {
+		return false
+	}
+	for _, r := range allowed {
+		if strings.ToLower(strings.TrimSpace(r)) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope จำกัด route สำหรับ API key: key ต้องมีทุก scope ที่ระบุ (login ปกติไม่มี scope ผ่านเสมอ)
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, s := range scopes {
+			if !hasScope(c, s) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "api key is missing scope " + s, "required_scope": s})
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// requireMethodScope: GET/HEAD ต้องมี read, method อื่นต้องมี write
+func requireMethodScope(read, write string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := write
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = read
+		}
+		RequireScope(scope)(c)
+	}
+}
+
+// hasScope: ไม่ได้เรียกด้วย API key = ไม่จำกัด scope, "x:write" ใช้แทน "x:read" ได้
+func hasScope(c *gin.Context, scope string) bool {
+	v, ok := c.Get("apiKeyScopes")
+	if !ok {
+		return true
+	}
+	scopes, _ := v.([]string)
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+		if res, ok := strings.CutSuffix(s, ":write"); ok && res+":read" == scope {
+			return true
+		}
+	}
+	return false
+}
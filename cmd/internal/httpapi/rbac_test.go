@@ -0,0 +1,98 @@
+package httpapi
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scopeContext สร้าง gin context ของ request; scopes = nil คือ login ปกติ (ไม่ใช่ API key)
+func scopeContext(method string, scopes []string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/api/judgments", nil)
+	if scopes != nil {
+		setAPIKeyContext(c, "k1", "u1", "a@example.com", "editor", scopes)
+	} else {
+		c.Set("userID", "u1")
+		c.Set("sessionID", "s1")
+	}
+	return c, w
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{"session is unrestricted", nil, scopeJudgmentsWrite, true},
+		{"exact scope", []string{scopeJudgmentsRead}, scopeJudgmentsRead, true},
+		{"write implies read", []string{scopeJudgmentsWrite}, scopeJudgmentsRead, true},
+		{"read does not imply write", []string{scopeJudgmentsRead}, scopeJudgmentsWrite, false},
+		{"other resource", []string{scopeUsersAdmin}, scopeJudgmentsRead, false},
+		{"empty key scopes", []string{}, scopeJudgmentsRead, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := scopeContext("GET", tt.scopes)
+			if got := hasScope(c, tt.scope); got != tt.want {
+				t.Fatalf("hasScope(%v, %q) = %v, want %v", tt.scopes, tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireMethodScope(t *testing.T) {
+	tests := []struct {
+		method string
+		scopes []string
+		want   int
+	}{
+		{"GET", []string{scopeJudgmentsRead}, 200},
+		{"HEAD", []string{scopeJudgmentsRead}, 200},
+		{"POST", []string{scopeJudgmentsRead}, 403},
+		{"PATCH", []string{scopeJudgmentsRead}, 403},
+		{"DELETE", []string{scopeJudgmentsRead}, 403},
+		{"POST", []string{scopeJudgmentsWrite}, 200},
+		{"GET", []string{scopeJudgmentsWrite}, 200},
+		{"GET", []string{scopeUsersAdmin}, 403},
+		{"DELETE", nil, 200},
+	}
+	for _, tt := range tests {
+		c, w := scopeContext(tt.method, tt.scopes)
+		requireMethodScope(scopeJudgmentsRead, scopeJudgmentsWrite)(c)
+		got := 200
+		if c.IsAborted() {
+			got = w.Code
+		}
+		if got != tt.want {
+			t.Errorf("%s with %v = %d, want %d", tt.method, tt.scopes, got, tt.want)
+		}
+	}
+}
+
+func TestRequireScopeNeedsAll(t *testing.T) {
+	c, w := scopeContext("POST", []string{scopeJudgmentsWrite})
+	RequireScope(scopeJudgmentsWrite, scopeUsersAdmin)(c)
+	if !c.IsAborted() || w.Code != 403 {
+		t.Fatalf("aborted = %v, status = %d; want 403", c.IsAborted(), w.Code)
+	}
+}
+
+func TestRequireSessionRejectsAPIKey(t *testing.T) {
+	c, w := scopeContext("POST", []string{scopeJudgmentsWrite, scopeUsersAdmin})
+	requireSession(c)
+	if !c.IsAborted() || w.Code != 403 {
+		t.Fatalf("api key: aborted = %v, status = %d; want 403", c.IsAborted(), w.Code)
+	}
+
+	c, _ = scopeContext("POST", nil)
+	requireSession(c)
+	if c.IsAborted() {
+		t.Fatal("login session was rejected")
+	}
+}
@@ -5,9 +5,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/mail"
+	"judgment-notes/cmd/internal/storage"
 )
 
-func NewRouter(pool *pgxpool.Pool) *gin.Engine {
+func NewRouter(pool *pgxpool.Pool, store storage.Store, mailer mail.Mailer) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger(), gin.Recovery())
 
@@ -15,7 +18,8 @@ func NewRouter(pool *pgxpool.Pool) *gin.Engine {
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization,If-Match,If-None-Match,Range,X-Checksum-SHA256,X-Calendar")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag,Content-Disposition,Content-Range,X-Checksum-SHA256,X-Calendar")
 		c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
 
 		if c.Request.Method == http.MethodOptions {
@@ -28,15 +32,36 @@ func NewRouter(pool *pgxpool.Pool) *gin.Engine {
 	api := r.Group("/api")
 
 	// Auth routes (public)
-	registerAuthRoutes(api, pool)
+	registerAuthRoutes(api, pool, mailer)
+
+	// ✅ Admin-only routes (จัดการ user) API key ต้องมี scope users:admin
+	users := api.Group("")
+	users.Use(AuthMiddleware(pool), RequireRole("admin"), RequireScope(scopeUsersAdmin))
+	registerUserAdminRoutes(users, pool, mailer)
+	registerUserSessionAdminRoutes(users, pool)
+	registerUserAPIKeyAdminRoutes(users, pool)
 
-	// ✅ Admin-only routes (จัดการ user)
+	// ✅ Admin-only routes (ข้อมูลคำพิพากษา/ทะเบียน) API key ใช้ scope judgments:read/write
 	admin := api.Group("")
-	admin.Use(AuthMiddleware(), RequireRole("admin"))
-	registerUserAdminRoutes(admin, pool)
+	admin.Use(AuthMiddleware(pool), RequireRole("admin"), requireMethodScope(scopeJudgmentsRead, scopeJudgmentsWrite))
+	registerJudgmentTrashRoutes(admin, pool, store)
 
 	// ✅ Judgments: user ก็ทำ CRUD ได้ แค่ต้อง login
-	registerJudgmentRoutes(api, pool) // เดี๋ยวไปแก้ใน registerJudgmentRoutes ให้แยก public/protected
+	registerJudgmentRoutes(api, pool, store) // เดี๋ยวไปแก้ใน registerJudgmentRoutes ให้แยก public/protected
+
+	// ประมวลกฎหมาย/มาตรา (แก้ชื่อมาตราได้เฉพาะ admin)
+	registerStatuteRoutes(api, admin, pool)
+
+	// ทะเบียนศาล (แก้ไขได้เฉพาะ admin)
+	registerCourtRoutes(api, admin, pool)
+
+	// ทะเบียน tag (autocomplete ได้ทุกคน, rename/merge เฉพาะ admin)
+	registerTagRoutes(api, admin, pool)
+
+	// รูปแบบเลขเอกสาร (ดูเลขถัดไปได้ทุกคนที่ login, แก้ไขได้เฉพาะ admin)
+	auth := api.Group("")
+	auth.Use(AuthMiddleware(pool), requireMethodScope(scopeJudgmentsRead, scopeJudgmentsWrite))
+	registerDocNoSchemeRoutes(auth, admin, pool)
 
 	r.GET("/api/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"ok": true})
@@ -0,0 +1,355 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// อายุ access token (JWT) และ refresh token เช่น ACCESS_TOKEN_TTL=15m, REFRESH_TOKEN_TTL=720h
+var (
+	accessTokenTTL  = envDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTokenTTL = envDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+)
+
+var errSessionInvalid = errors.New("invalid or expired refresh token")
+
+// บันทึก last_seen_at ไม่บ่อยกว่านี้ (ไม่ต้องเขียน DB ทุก request)
+const sessionSeenInterval = time.Minute
+
+type Session struct {
+	ID          string    `json:"id"`
+	DeviceLabel *string   `json:"device_label"`
+	UserAgent   *string   `json:"user_agent"`
+	IP          *string   `json:"ip"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Current     bool      `json:"current"` // session ของ token ที่ใช้เรียก
+}
+
+type refreshPayload struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+func registerSessionRoutes(api *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.POST("/auth/refresh", func(c *gin.Context) { refreshSession(c, pool) })
+	api.POST("/auth/logout", mfaSetupMiddleware(pool), func(c *gin.Context) { logout(c, pool) })
+	api.POST("/auth/logout-all", mfaSetupMiddleware(pool), func(c *gin.Context) { logoutAll(c, pool) })
+	api.GET("/auth/sessions", mfaSetupMiddleware(pool), func(c *gin.Context) {
+		listSessions(c, pool, c.GetString("userID"))
+	})
+	api.DELETE("/auth/sessions/:id", mfaSetupMiddleware(pool), func(c *gin.Context) {
+		revokeSession(c, pool, c.GetString("userID"), c.Param("id"))
+	})
+}
+
+func registerUserSessionAdminRoutes(admin *gin.RouterGroup, pool *pgxpool.Pool) {
+	admin.GET("/users/:id/sessions", func(c *gin.Context) { listSessions(c, pool, c.Param("id")) })
+	admin.DELETE("/users/:id/sessions", func(c *gin.Context) { adminRevokeUserSessions(c, pool) })
+	admin.DELETE("/users/:id/sessions/:sid", func(c *gin.Context) {
+		revokeSession(c, pool, c.Param("id"), c.Param("sid"))
+	})
+}
+
+// startSession สร้าง session ใหม่แล้วตอบ access token + refresh token (ใช้ตอน login / register)
+// label ว่างใช้ชื่อ browser/OS จาก User-Agent แทน
+func startSession(c *gin.Context, pool *pgxpool.Pool, user User, label string, status int) {
+	refresh, err := newOpaqueToken()
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to generate token"})
+		return
+	}
+	ua := c.Request.UserAgent()
+	if label = strings.TrimSpace(label); label == "" {
+		label = deviceLabel(ua)
+	}
+	var sid string
+	err = pool.QueryRow(c, `
+INSERT INTO sessions (user_id, refresh_hash, expires_at, ip, user_agent, device_label)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id`, user.ID, hashToken(refresh), time.Now().Add(refreshTokenTTL),
+		nullIfEmpty(c.ClientIP()), nullIfEmpty(ua), nullIfEmpty(label)).Scan(&sid)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	respondTokens(c, user, sid, refresh, status)
+}
+
+func respondTokens(c *gin.Context, user User, sid, refresh string, status int) {
+	token, err := signAccessToken(user, sid)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to generate token"})
+		return
+	}
+	c.JSON(status, gin.H{
+		"token":         token,
+		"expires_in":    int(accessTokenTTL.Seconds()),
+		"refresh_token": refresh,
+		"user":          user,
+	})
+}
+
+func signAccessToken(user User, sid string) (string, error) {
+	jti, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   user.ID,
+		"email": user.Email,
+		"name":  user.Name,
+		"role":  user.Role,
+		"sid":   sid,
+		"jti":   jti,
+		"iat":   now.Unix(),
+		"exp":   now.Add(accessTokenTTL).Unix(),
+	})
+	return token.SignedString(jwtSecret)
+}
+
+// refreshSession: POST /auth/refresh {"refresh_token": "..."} ได้ access token และ refresh token ใหม่
+// refresh token เก่าใช้ไม่ได้อีก ถ้ามีคนเอา token เก่ามาใช้ซ้ำ (ถูกขโมย) session นั้นถูกยกเลิกทั้ง session
+func refreshSession(c *gin.Context, pool *pgxpool.Pool) {
+	var in refreshPayload
+	if err := c.ShouldBindJSON(&in); err != nil || in.RefreshToken == "" {
+		c.JSON(400, gin.H{"error": "refresh_token is required"})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	user, sid, refresh, err := rotateRefreshToken(c, tx, hashToken(in.RefreshToken), c.ClientIP())
+	if errors.Is(err, errSessionInvalid) {
+		// commit การยกเลิก session กรณี token ถูกใช้ซ้ำ
+		_ = tx.Commit(c)
+		c.JSON(401, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	respondTokens(c, user, sid, refresh, 200)
+}
+
+func rotateRefreshToken(ctx context.Context, tx pgx.Tx, hash, ip string) (User, string, string, error) {
+	var user User
+	var sid string
+	err := tx.QueryRow(ctx, `
+SELECT s.id, u.id, u.email, u.name, u.role, u.avatar_url, u.totp_enabled_at IS NOT NULL, u.email_verified_at IS NOT NULL, u.created_at
+FROM sessions s JOIN users u ON u.id = s.user_id
+WHERE s.refresh_hash = $1 AND s.revoked_at IS NULL AND s.expires_at > now()
+FOR UPDATE OF s`, hash).Scan(&sid, &user.ID, &user.Email, &user.Name, &user.Role, &user.AvatarURL, &user.MFAEnabled, &user.EmailVerified, &user.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := tx.Exec(ctx, `
+UPDATE sessions SET revoked_at = now()
+WHERE previous_hash = $1 AND revoked_at IS NULL`, hash); err != nil {
+			return user, "", "", err
+		}
+		return user, "", "", errSessionInvalid
+	}
+	if err != nil {
+		return user, "", "", err
+	}
+
+	refresh, err := newOpaqueToken()
+	if err != nil {
+		return user, "", "", err
+	}
+	_, err = tx.Exec(ctx, `
+UPDATE sessions
+SET previous_hash = refresh_hash, refresh_hash = $2, last_used_at = now(), last_seen_at = now(),
+    expires_at = $3, ip = COALESCE($4, ip)
+WHERE id = $1`, sid, hashToken(refresh), time.Now().Add(refreshTokenTTL), nullIfEmpty(ip))
+	return user, sid, refresh, err
+}
+
+// logout ยกเลิก session ของ token ที่ใช้เรียก
+func logout(c *gin.Context, pool *pgxpool.Pool) {
+	if _, err := pool.Exec(c, `UPDATE sessions SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`,
+		c.GetString("sessionID")); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "logged out"})
+}
+
+// logoutAll ยกเลิกทุก session และ API key ของ user
+func logoutAll(c *gin.Context, pool *pgxpool.Pool) {
+	n, keys, err := revokeUserAccess(c, pool, c.GetString("userID"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"message": "logged out everywhere", "revoked": n, "revoked_api_keys": keys})
+}
+
+func adminRevokeUserSessions(c *gin.Context, pool *pgxpool.Pool) {
+	n, keys, err := revokeUserAccess(c, pool, c.Param("id"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"revoked": n, "revoked_api_keys": keys})
+}
+
+// revokeUserAccess ยกเลิก session และ API key ใน transaction เดียวกัน
+func revokeUserAccess(ctx context.Context, pool *pgxpool.Pool, userID string) (sessions, keys int64, err error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+	if sessions, err = revokeUserSessions(ctx, tx, userID); err != nil {
+		return 0, 0, err
+	}
+	if keys, err = revokeUserAPIKeys(ctx, tx, userID); err != nil {
+		return 0, 0, err
+	}
+	return sessions, keys, tx.Commit(ctx)
+}
+
+func revokeUserSessions(ctx context.Context, q querier, userID string) (int64, error) {
+	ct, err := q.Exec(ctx, `
+UPDATE sessions SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+// activeSession คืน role/email ปัจจุบันของ user ถ้า session ยังใช้ได้ (เปลี่ยน role แล้วมีผลทันที)
+// mfaPending = role บังคับ 2FA แต่ user ยังไม่ได้เปิด และอัปเดต last_seen_at / ip ของ session
+func activeSession(ctx context.Context, pool *pgxpool.Pool, sid, userID, ip string) (role, email string, mfaPending bool, err error) {
+	var lastSeen time.Time
+	err = pool.QueryRow(ctx, `
+SELECT u.role, u.email, s.last_seen_at,
+       u.totp_enabled_at IS NULL AND EXISTS (SELECT 1 FROM roles r WHERE r.name = u.role AND r.require_mfa)
+FROM sessions s JOIN users u ON u.id = s.user_id
+WHERE s.id = $1 AND u.id = $2 AND s.revoked_at IS NULL AND s.expires_at > now()`,
+		sid, userID).Scan(&role, &email, &lastSeen, &mfaPending)
+	if err != nil {
+		return "", "", false, err
+	}
+	if time.Since(lastSeen) > sessionSeenInterval {
+		_, err = pool.Exec(ctx, `UPDATE sessions SET last_seen_at = now(), ip = COALESCE($2, ip) WHERE id = $1`,
+			sid, nullIfEmpty(ip))
+	}
+	return role, email, mfaPending, err
+}
+
+// listSessions แสดง session ที่ยังใช้ได้ของ user ล่าสุดก่อน
+func listSessions(c *gin.Context, pool *pgxpool.Pool, userID string) {
+	rows, err := pool.Query(c, `
+SELECT id, device_label, user_agent, ip, created_at, last_seen_at, expires_at
+FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+ORDER BY last_seen_at DESC`, userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	current := c.GetString("sessionID")
+	out := make([]Session, 0)
+	for rows.Next() {
+		var s Session
+		if err := rows.Scan(&s.ID, &s.DeviceLabel, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		s.Current = s.ID == current
+		out = append(out, s)
+	}
+	c.JSON(200, out)
+}
+
+// revokeSession ยกเลิก session เดียวของ user (เช่น เครื่องที่ลืม logout)
+func revokeSession(c *gin.Context, pool *pgxpool.Pool, userID, sid string) {
+	ct, err := pool.Exec(c, `
+UPDATE sessions SET revoked_at = now()
+WHERE id::text = $1 AND user_id = $2 AND revoked_at IS NULL`, sid, userID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if ct.RowsAffected() == 0 {
+		c.JSON(404, gin.H{"error": "session not found"})
+		return
+	}
+	c.Status(204)
+}
+
+// deviceLabel ตั้งชื่ออุปกรณ์คร่าว ๆ จาก User-Agent เช่น "Chrome on Windows"
+func deviceLabel(ua string) string {
+	browser := firstMatch(ua, [][2]string{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Chrome/", "Chrome"}, {"Firefox/", "Firefox"},
+		{"Safari/", "Safari"}, {"curl/", "curl"},
+	})
+	platform := firstMatch(ua, [][2]string{
+		{"Windows", "Windows"}, {"Android", "Android"}, {"iPhone", "iOS"}, {"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"}, {"CrOS", "ChromeOS"}, {"Linux", "Linux"},
+	})
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	}
+	return platform
+}
+
+func firstMatch(s string, pairs [][2]string) string {
+	for _, p := range pairs {
+		if strings.Contains(s, p[0]) {
+			return p[1]
+		}
+	}
+	return ""
+}
+
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken: token สุ่มยาวพอแล้ว ใช้ sha256 ตรง ๆ (ไม่ต้อง bcrypt) เพื่อค้นด้วย index ได้
+func hashToken(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,273 @@
+package httpapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"judgment-notes/cmd/internal/legal"
+)
+
+type Statute struct {
+	Code          string `json:"code"`
+	Abbr          string `json:"abbr"`
+	Name          string `json:"name"`
+	SectionCount  int    `json:"section_count"`
+	JudgmentCount int    `json:"judgment_count"`
+}
+
+type StatuteSection struct {
+	Code          string  `json:"code"`
+	Abbr          string  `json:"abbr"`
+	Section       string  `json:"section"`
+	Title         *string `json:"title"`
+	Ref           string  `json:"ref"`            // ใช้กับ ?statute= ของ GET /judgments เช่น civil:420
+	JudgmentCount int     `json:"judgment_count"` // ไม่นับ judgment ในถังขยะ
+}
+
+const maxTopSections = 100
+
+// เรียงมาตราตามตัวเลข (188 < 188/1 < 189)
+const sectionOrder = `string_to_array(s.section, '/')::int[]`
+
+func registerStatuteRoutes(api, admin *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.GET("/statutes", func(c *gin.Context) { listStatutes(c, pool) })
+	api.GET("/statutes/top", func(c *gin.Context) { topStatuteSections(c, pool) })
+	api.GET("/statutes/:code/sections", func(c *gin.Context) { listStatuteSections(c, pool) })
+	api.GET("/judgments/:id/statutes", func(c *gin.Context) { listJudgmentStatutes(c, pool) })
+	admin.PUT("/statutes/:code/sections", func(c *gin.Context) { upsertStatuteSection(c, pool) })
+}
+
+func listStatutes(c *gin.Context, pool *pgxpool.Pool) {
+	rows, err := pool.Query(c, `
+SELECT st.code, st.abbr, st.name,
+  (SELECT COUNT(*) FROM statute_sections s WHERE s.statute_code = st.code),
+  (SELECT COUNT(DISTINCT js.judgment_id) FROM judgment_statutes js
+     JOIN statute_sections s ON s.id = js.section_id
+     JOIN judgments j ON j.id = js.judgment_id AND j.deleted_at IS NULL
+   WHERE s.statute_code = st.code)
+FROM statutes st
+ORDER BY st.code`)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	out := make([]Statute, 0)
+	for rows.Next() {
+		var s Statute
+		if err := rows.Scan(&s.Code, &s.Abbr, &s.Name, &s.SectionCount, &s.JudgmentCount); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		out = append(out, s)
+	}
+	c.JSON(200, out)
+}
+
+// topStatuteSections: มาตราที่ถูกอ้างมากที่สุด รับ filter ชุดเดียวกับ GET /judgments
+// เช่น ?court=ศาลฎีกา&date_from=2020-01-01&code=civil
+func topStatuteSections(c *gin.Context, pool *pgxpool.Pool) {
+	f, err := parseJudgmentFilter(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > maxTopSections {
+		limit = maxTopSections
+	}
+
+	q := f.build()
+	codeCond := ""
+	if code := c.Query("code"); code != "" {
+		if _, ok := legal.StatuteByCode(code); !ok {
+			c.JSON(400, gin.H{"error": "unknown statute: " + code})
+			return
+		}
+		codeCond = " AND s.statute_code = " + q.Args.add(code)
+	}
+
+	sections, err := querySections(c, pool, `
+SELECT s.statute_code, st.abbr, s.section, s.title, COUNT(*)
+FROM judgment_statutes js
+JOIN statute_sections s ON s.id = js.section_id
+JOIN statutes st ON st.code = s.statute_code
+WHERE js.judgment_id IN (SELECT id FROM judgments WHERE `+q.Where+`)`+codeCond+`
+GROUP BY s.id, st.abbr
+ORDER BY COUNT(*) DESC, s.statute_code, `+sectionOrder+`
+LIMIT `+itoa(limit), q.Args...)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, sections)
+}
+
+// listStatuteSections: มาตราทั้งหมดของประมวลที่มีในระบบ เรียงตามเลขมาตรา
+func listStatuteSections(c *gin.Context, pool *pgxpool.Pool) {
+	code := c.Param("code")
+	if _, ok := legal.StatuteByCode(code); !ok {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	sections, err := querySections(c, pool, `
+SELECT s.statute_code, st.abbr, s.section, s.title,
+  (SELECT COUNT(*) FROM judgment_statutes js
+     JOIN judgments j ON j.id = js.judgment_id AND j.deleted_at IS NULL
+   WHERE js.section_id = s.id)
+FROM statute_sections s
+JOIN statutes st ON st.code = s.statute_code
+WHERE s.statute_code = $1
+ORDER BY `+sectionOrder, code)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, sections)
+}
+
+func listJudgmentStatutes(c *gin.Context, pool *pgxpool.Pool) {
+	id := c.Param("id")
+	if !judgmentExists(c, pool, id) {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	sections, err := querySections(c, pool, `
+SELECT s.statute_code, st.abbr, s.section, s.title,
+  (SELECT COUNT(*) FROM judgment_statutes o
+     JOIN judgments j ON j.id = o.judgment_id AND j.deleted_at IS NULL
+   WHERE o.section_id = s.id)
+FROM judgment_statutes js
+JOIN statute_sections s ON s.id = js.section_id
+JOIN statutes st ON st.code = s.statute_code
+WHERE js.judgment_id = $1
+ORDER BY s.statute_code, `+sectionOrder, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, sections)
+}
+
+// upsertStatuteSection: admin ตั้งชื่อมาตรา (สร้างมาตราใหม่ได้ถ้ายังไม่มี)
+// body: {"section": "420", "title": "ละเมิด"}
+func upsertStatuteSection(c *gin.Context, pool *pgxpool.Pool) {
+	code := c.Param("code")
+	if _, ok := legal.StatuteByCode(code); !ok {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var in struct {
+		Section string  `json:"section"`
+		Title   *string `json:"title"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	section := legal.NormalizeSection(in.Section)
+	if section == "" {
+		c.JSON(400, gin.H{"error": "invalid section"})
+		return
+	}
+	if in.Title != nil {
+		if t := strings.TrimSpace(*in.Title); t == "" {
+			in.Title = nil
+		} else {
+			in.Title = &t
+		}
+	}
+
+	_, err := pool.Exec(c, `
+INSERT INTO statute_sections (statute_code, section, title) VALUES ($1, $2, $3)
+ON CONFLICT (statute_code, section) DO UPDATE SET title = EXCLUDED.title`, code, section, in.Title)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	sections, err := querySections(c, pool, `
+SELECT s.statute_code, st.abbr, s.section, s.title,
+  (SELECT COUNT(*) FROM judgment_statutes js
+     JOIN judgments j ON j.id = js.judgment_id AND j.deleted_at IS NULL
+   WHERE js.section_id = s.id)
+FROM statute_sections s
+JOIN statutes st ON st.code = s.statute_code
+WHERE s.statute_code = $1 AND s.section = $2`, code, section)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if len(sections) == 0 {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(200, sections[0])
+}
+
+func querySections(ctx context.Context, pool *pgxpool.Pool, sql string, args ...any) ([]StatuteSection, error) {
+	rows, err := pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]StatuteSection, 0)
+	for rows.Next() {
+		var s StatuteSection
+		if err := rows.Scan(&s.Code, &s.Abbr, &s.Section, &s.Title, &s.JudgmentCount); err != nil {
+			return nil, err
+		}
+		s.Ref = s.Code + ":" + s.Section
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// syncStatuteRefs ตั้งมาตราที่ judgment อ้างให้ตรงกับที่พบในเนื้อหา (เรียกจาก reindexJudgment)
+// มาตราที่ยังไม่มีใน registry จะถูกเพิ่มให้ (ยังไม่มี title)
+func syncStatuteRefs(ctx context.Context, q querier, id string, texts ...string) error {
+	refs := legal.FindSections(texts...)
+	codes := make([]string, len(refs))
+	sections := make([]string, len(refs))
+	for i, r := range refs {
+		codes[i], sections[i] = r.Code, r.Section
+	}
+
+	if len(refs) > 0 {
+		_, err := q.Exec(ctx, `
+INSERT INTO statute_sections (statute_code, section)
+SELECT * FROM unnest($1::text[], $2::text[])
+ON CONFLICT (statute_code, section) DO NOTHING`, codes, sections)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := q.Exec(ctx, `
+DELETE FROM judgment_statutes js
+USING statute_sections s
+WHERE js.section_id = s.id AND js.judgment_id = $1
+  AND (s.statute_code, s.section) NOT IN (SELECT * FROM unnest($2::text[], $3::text[]))`, id, codes, sections)
+	if err != nil || len(refs) == 0 {
+		return err
+	}
+
+	_, err = q.Exec(ctx, `
+INSERT INTO judgment_statutes (judgment_id, section_id)
+SELECT $1::uuid, s.id
+FROM statute_sections s
+JOIN unnest($2::text[], $3::text[]) AS r(code, section) ON s.statute_code = r.code AND s.section = r.section
+ON CONFLICT DO NOTHING`, id, codes, sections)
+	return err
+}
@@ -0,0 +1,591 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Tag struct {
+	ID         int       `json:"id"`
+	NameTH     string    `json:"name_th"` // ชื่อมาตรฐานที่เก็บใน judgments.tags
+	NameEN     *string   `json:"name_en"`
+	ParentID   *int      `json:"parent_id"`
+	Synonyms   []string  `json:"synonyms"`
+	UsageCount int       `json:"usage_count"` // ไม่นับ judgment ในถังขยะ
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type tagPayload struct {
+	NameTH   string   `json:"name_th"`
+	NameEN   *string  `json:"name_en"`
+	ParentID *int     `json:"parent_id"`
+	Synonyms []string `json:"synonyms"`
+}
+
+const maxTagSuggestions = 50
+
+const tagColumns = `t.id, t.name_th, t.name_en, t.parent_id,
+       ARRAY(SELECT s.synonym FROM tag_synonyms s WHERE s.tag_id = t.id ORDER BY s.synonym),
+       (SELECT COUNT(*) FROM judgments j WHERE j.tags @> ARRAY[t.name_th] AND j.deleted_at IS NULL),
+       t.created_at, t.updated_at`
+
+func scanTag(row pgx.Row) (Tag, error) {
+	var t Tag
+	err := row.Scan(&t.ID, &t.NameTH, &t.NameEN, &t.ParentID, &t.Synonyms, &t.UsageCount, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+func registerTagRoutes(api, admin *gin.RouterGroup, pool *pgxpool.Pool) {
+	api.GET("/tags", func(c *gin.Context) { listTags(c, pool) })
+	api.GET("/tags/:id", func(c *gin.Context) { getTag(c, pool) })
+	admin.POST("/tags", func(c *gin.Context) { createTag(c, pool) })
+	admin.PUT("/tags/:id", func(c *gin.Context) { updateTag(c, pool) })
+	admin.POST("/tags/:id/merge", func(c *gin.Context) { mergeTag(c, pool) })
+	admin.DELETE("/tags/:id", func(c *gin.Context) { deleteTag(c, pool) })
+}
+
+// listTags: autocomplete ?q=สัญ&limit=10 (ค้นทั้งชื่อไทย อังกฤษ และคำพ้อง) หรือ ?parent_id=3 / ?parent_id=root
+func listTags(c *gin.Context, pool *pgxpool.Pool) {
+	conds := []string{"true"}
+	var args sqlArgs
+	order := "usage DESC, t.name_th"
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		p := args.add("%" + likeEscape(q) + "%")
+		conds = append(conds, `(t.name_th ILIKE `+p+` OR t.name_en ILIKE `+p+`
+     OR EXISTS (SELECT 1 FROM tag_synonyms s WHERE s.tag_id = t.id AND s.synonym ILIKE `+p+`))`)
+		// ขึ้นต้นด้วยคำที่พิมพ์มาก่อน
+		order = "(t.name_th ILIKE " + args.add(likeEscape(q)+"%") + ") DESC, " + order
+	}
+	switch parent := c.Query("parent_id"); parent {
+	case "":
+	case "root":
+		conds = append(conds, "t.parent_id IS NULL")
+	default:
+		conds = append(conds, "t.parent_id = "+args.add(parent)+"::int")
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > maxTagSuggestions {
+		c.JSON(400, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxTagSuggestions)})
+		return
+	}
+
+	rows, err := pool.Query(c, `
+SELECT * FROM (
+  SELECT `+tagColumns+`
+  FROM tags t
+  WHERE `+strings.Join(conds, " AND ")+`
+) t (id, name_th, name_en, parent_id, synonyms, usage, created_at, updated_at)
+ORDER BY `+order+`
+LIMIT `+args.add(limit), args...)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	out := make([]Tag, 0)
+	for rows.Next() {
+		t, err := scanTag(rows)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		out = append(out, t)
+	}
+	c.JSON(200, out)
+}
+
+// getTag คืน tag พร้อม tag ลูก
+func getTag(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	t, err := loadTag(c, pool, id)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+
+	rows, err := pool.Query(c, `SELECT `+tagColumns+` FROM tags t WHERE t.parent_id=$1 ORDER BY t.name_th`, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	children := make([]Tag, 0)
+	for rows.Next() {
+		ch, err := scanTag(rows)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		children = append(children, ch)
+	}
+	c.JSON(200, gin.H{"tag": t, "children": children})
+}
+
+func loadTag(ctx context.Context, q querier, id int) (Tag, error) {
+	return scanTag(q.QueryRow(ctx, `SELECT `+tagColumns+` FROM tags t WHERE t.id=$1`, id))
+}
+
+func createTag(c *gin.Context, pool *pgxpool.Pool) {
+	var in tagPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := validateTagPayload(&in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	if !checkTagNames(c, tx, 0, in) {
+		return
+	}
+	var id int
+	err = tx.QueryRow(c, `
+INSERT INTO tags (name_th, name_en, parent_id) VALUES ($1,$2,$3)
+RETURNING id`, in.NameTH, in.NameEN, in.ParentID).Scan(&id)
+	if err != nil {
+		tagWriteFailed(c, err)
+		return
+	}
+	saveTag(c, pool, tx, id, in, 201)
+}
+
+// updateTag: เปลี่ยน name_th แล้ว judgment ทุกตัวที่ใช้ tag นี้เปลี่ยนตาม และชื่อเดิมกลายเป็นคำพ้อง
+func updateTag(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var in tagPayload
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(400, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := validateTagPayload(&in); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	var oldName string
+	if err := tx.QueryRow(c, `SELECT name_th FROM tags WHERE id=$1 FOR UPDATE`, id).Scan(&oldName); err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if in.ParentID != nil {
+		cycle, err := tagIsDescendant(c, tx, *in.ParentID, id)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		if cycle {
+			c.JSON(400, gin.H{"error": "parent_id would create a cycle"})
+			return
+		}
+	}
+
+	var renamed bool
+	err = tx.QueryRow(c, `SELECT tag_key($1) IS DISTINCT FROM tag_key($2)`, oldName, in.NameTH).Scan(&renamed)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if renamed && !containsString(in.Synonyms, oldName) {
+		in.Synonyms = append(in.Synonyms, oldName)
+	}
+	if !checkTagNames(c, tx, id, in) {
+		return
+	}
+
+	if _, err := tx.Exec(c, `
+UPDATE tags SET name_th=$2, name_en=$3, parent_id=$4, updated_at=now()
+WHERE id=$1`, id, in.NameTH, in.NameEN, in.ParentID); err != nil {
+		tagWriteFailed(c, err)
+		return
+	}
+	if _, err := tx.Exec(c, `DELETE FROM tag_synonyms WHERE tag_id=$1`, id); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := rewriteJudgmentTags(c, tx, oldName, in.NameTH, c.GetString("userID")); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	saveTag(c, pool, tx, id, in, 200)
+}
+
+// mergeTag: POST /tags/:id/merge {"into": 12} ย้าย judgment, คำพ้อง และ tag ลูกไปที่ tag ปลายทาง
+// แล้วลบ tag ต้นทาง (ชื่อของ tag ต้นทางกลายเป็นคำพ้องของ tag ปลายทาง)
+func mergeTag(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var in struct {
+		Into int `json:"into"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil || in.Into == 0 {
+		c.JSON(400, gin.H{"error": "into is required"})
+		return
+	}
+	if in.Into == id {
+		c.JSON(400, gin.H{"error": "cannot merge a tag into itself"})
+		return
+	}
+
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
+	var from, into string
+	var fromEN *string
+	var fromParent *int
+	err = tx.QueryRow(c, `SELECT name_th, name_en, parent_id FROM tags WHERE id=$1 FOR UPDATE`, id).Scan(&from, &fromEN, &fromParent)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	if err := tx.QueryRow(c, `SELECT name_th FROM tags WHERE id=$1 FOR UPDATE`, in.Into).Scan(&into); err != nil {
+		c.JSON(400, gin.H{"error": "target tag not found"})
+		return
+	}
+	// ปลายทางเป็นลูกหลานของต้นทาง: ย้าย tag ลูกไปใต้ปลายทางจะวนกลับ
+	descendant, err := tagIsDescendant(c, tx, in.Into, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if descendant {
+		c.JSON(400, gin.H{"error": "cannot merge a tag into its own descendant"})
+		return
+	}
+
+	rewritten, err := rewriteJudgmentTags(c, tx, from, into, c.GetString("userID"))
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	steps := []struct {
+		sql  string
+		args []any
+	}{
+		{`UPDATE tags SET parent_id=$2 WHERE parent_id=$1`, []any{id, in.Into}},
+		{`UPDATE tag_synonyms SET tag_id=$2 WHERE tag_id=$1`, []any{id, in.Into}},
+		{`DELETE FROM tags WHERE id=$1`, []any{id}},
+		{`
+INSERT INTO tag_synonyms (tag_id, synonym)
+SELECT t.id, x FROM tags t, unnest(ARRAY[$2, $3]::text[]) AS x
+WHERE t.id = $1 AND x IS NOT NULL
+  AND tag_key(x) <> tag_key(t.name_th) AND tag_key(x) IS DISTINCT FROM tag_key(t.name_en)
+ON CONFLICT DO NOTHING`, []any{in.Into, from, fromEN}},
+		{`UPDATE tags SET updated_at=now() WHERE id=$1`, []any{in.Into}},
+	}
+	for _, s := range steps {
+		if _, err := tx.Exec(c, s.sql, s.args...); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	t, err := loadTag(c, tx, in.Into)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if rewritten > 0 {
+		requestReindex()
+	}
+	c.JSON(200, gin.H{"tag": t, "judgments_updated": rewritten})
+}
+
+// deleteTag: ลบได้เฉพาะ tag ที่ไม่มี judgment ใช้ (รวมในถังขยะ) และไม่มี tag ลูก ไม่งั้นให้ merge แทน
+func deleteTag(c *gin.Context, pool *pgxpool.Pool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	var inUse bool
+	err = pool.QueryRow(c, `
+SELECT EXISTS (SELECT 1 FROM judgments j, tags t WHERE t.id=$1 AND j.tags @> ARRAY[t.name_th])
+    OR EXISTS (SELECT 1 FROM tags WHERE parent_id=$1)`, id).Scan(&inUse)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if inUse {
+		c.JSON(409, gin.H{"error": "tag is used by judgments or has child tags; merge it instead"})
+		return
+	}
+	ct, err := pool.Exec(c, `DELETE FROM tags WHERE id=$1`, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if ct.RowsAffected() == 0 {
+		c.JSON(404, gin.H{"error": "not found"})
+		return
+	}
+	c.Status(204)
+}
+
+// saveTag บันทึกคำพ้อง, ให้ judgment ที่ใช้ tag นี้ index ใหม่ (ชื่อ/คำพ้องอยู่ใน search_vector) แล้ว commit
+func saveTag(c *gin.Context, pool *pgxpool.Pool, tx pgx.Tx, id int, in tagPayload, status int) {
+	for _, s := range in.Synonyms {
+		if _, err := tx.Exec(c, `INSERT INTO tag_synonyms (tag_id, synonym) VALUES ($1,$2)`, id, s); err != nil {
+			tagWriteFailed(c, err)
+			return
+		}
+	}
+	stale, err := tx.Exec(c, `UPDATE judgments SET index_version = 0 WHERE tags @> ARRAY[$1::text]`, in.NameTH)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := loadTag(c, tx, id)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	if stale.RowsAffected() > 0 {
+		requestReindex()
+	}
+	c.JSON(status, t)
+}
+
+// checkTagNames: ชื่อและคำพ้องต้องไม่ชนกับ tag อื่น (ชนกันแปลว่าเป็น tag เดียวกัน ให้ merge)
+func checkTagNames(c *gin.Context, q querier, id int, in tagPayload) bool {
+	names := []string{in.NameTH}
+	if in.NameEN != nil {
+		names = append(names, *in.NameEN)
+	}
+	var taken bool
+	err := q.QueryRow(c, `
+SELECT EXISTS (
+  SELECT 1 FROM tags t, unnest($2::text[]) AS x
+  WHERE t.id <> $1 AND tag_key(x) IN (tag_key(t.name_th), tag_key(t.name_en)))
+OR EXISTS (
+  SELECT 1 FROM tag_synonyms s, unnest($3::text[]) AS x
+  WHERE s.tag_id <> $1 AND s.synonym_key = tag_key(x))`,
+		id, append(names, in.Synonyms...), names).Scan(&taken)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	if taken {
+		c.JSON(409, gin.H{"error": "name or synonym belongs to another tag; merge the tags instead"})
+		return false
+	}
+	return true
+}
+
+// tagIsDescendant: tag id อยู่ใต้ ancestor (หรือเป็นตัวเดียวกัน) หรือไม่
+func tagIsDescendant(ctx context.Context, q querier, id, ancestor int) (bool, error) {
+	var found bool
+	err := q.QueryRow(ctx, `
+WITH RECURSIVE up AS (
+  SELECT id, parent_id FROM tags WHERE id = $1
+  UNION
+  SELECT t.id, t.parent_id FROM tags t JOIN up ON t.id = up.parent_id
+)
+SELECT EXISTS (SELECT 1 FROM up WHERE id = $2)`, id, ancestor).Scan(&found)
+	return found, err
+}
+
+// rewriteJudgmentTags เปลี่ยนชื่อ tag ในทุก judgment (รวมในถังขยะ) ตัดตัวซ้ำโดยคงลำดับเดิม
+// แถวที่เปลี่ยนได้ version ใหม่และ revision (เหมือนแก้ทีละเรื่อง) แล้วจะถูก index ใหม่ภายหลัง
+func rewriteJudgmentTags(ctx context.Context, tx pgx.Tx, from, to, userID string) (int64, error) {
+	if from == to {
+		return 0, nil
+	}
+	rows, err := tx.Query(ctx, `
+UPDATE judgments j SET tags = ARRAY(
+    SELECT u.x FROM unnest(array_replace(j.tags, $1, $2)) WITH ORDINALITY AS u(x, n)
+    GROUP BY u.x ORDER BY min(u.n)
+  ),
+  version = j.version + 1, index_version = 0, updated_at = now(), updated_by = $3
+WHERE j.tags @> ARRAY[$1::text]
+RETURNING j.id::text`, from, to, nullIfEmpty(userID))
+	if err != nil {
+		return 0, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+	if err := recordRevisions(ctx, tx, ids, "update", userID); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// lookupTags แปลง tag เป็นชื่อมาตรฐานโดยไม่สร้าง tag ใหม่ (ตัดตัวซ้ำ คงลำดับ)
+// tag ที่ไม่มีในทะเบียนคืนแยกไว้ใน unknown
+func lookupTags(ctx context.Context, q querier, tags []string) (known, unknown []string, err error) {
+	known, unknown = []string{}, []string{}
+	if len(tags) == 0 {
+		return known, unknown, nil
+	}
+	rows, err := q.Query(ctx, `
+SELECT btrim(u.x), t.name_th
+FROM unnest($1::text[]) WITH ORDINALITY AS u(x, n)
+LEFT JOIN tags t ON t.id = resolve_tag(u.x)
+WHERE tag_key(u.x) IS NOT NULL
+ORDER BY u.n`, tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var raw string
+		var name *string
+		if err := rows.Scan(&raw, &name); err != nil {
+			return nil, nil, err
+		}
+		if name == nil {
+			if !containsString(unknown, raw) {
+				unknown = append(unknown, raw)
+			}
+		} else if !containsString(known, *name) {
+			known = append(known, *name)
+		}
+	}
+	return known, unknown, rows.Err()
+}
+
+// TAG_AUTO_CREATE=true: tag ที่ยังไม่มีในทะเบียนถูกเพิ่มให้อัตโนมัติตอนบันทึก judgment
+// ค่าเริ่มต้นไม่รับ (ตอบ 400) กันทะเบียนรกด้วยคำสะกดผิด ต้องให้ admin เพิ่ม tag ก่อน
+var tagAutoCreate = strings.EqualFold(getEnv("TAG_AUTO_CREATE", "false"), "true")
+
+// unknownTagsError: มี tag ที่ไม่อยู่ในทะเบียน
+type unknownTagsError struct {
+	tags []string
+}
+
+func (e *unknownTagsError) Error() string {
+	return "unknown tags: " + strings.Join(e.tags, ", ")
+}
+
+// resolveTags แปลง tag ที่ผู้ใช้ส่งมาเป็นชื่อมาตรฐาน (ตัดตัวซ้ำ คงลำดับ)
+// tag ที่ไม่มีในทะเบียนคืน *unknownTagsError ยกเว้น create=true ซึ่งจะเพิ่มให้ (admin จัดหมวด/merge ภายหลัง)
+func resolveTags(ctx context.Context, q querier, tags []string, create bool) ([]string, error) {
+	if len(tags) == 0 {
+		return []string{}, nil
+	}
+	if !create {
+		known, unknown, err := lookupTags(ctx, q, tags)
+		if err != nil {
+			return nil, err
+		}
+		if len(unknown) > 0 {
+			return nil, &unknownTagsError{tags: unknown}
+		}
+		return known, nil
+	}
+	_, err := q.Exec(ctx, `
+INSERT INTO tags (name_th)
+SELECT DISTINCT ON (tag_key(u.x)) btrim(u.x)
+FROM unnest($1::text[]) WITH ORDINALITY AS u(x, n)
+WHERE tag_key(u.x) IS NOT NULL AND resolve_tag(u.x) IS NULL
+ORDER BY tag_key(u.x), u.n
+ON CONFLICT DO NOTHING`, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx, `
+SELECT t.name_th
+FROM unnest($1::text[]) WITH ORDINALITY AS u(x, n)
+JOIN tags t ON t.id = resolve_tag(u.x)
+GROUP BY t.name_th
+ORDER BY min(u.n)`, tags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		out = append(out, name)
+	}
+	return out, rows.Err()
+}
+
+func validateTagPayload(in *tagPayload) error {
+	in.NameTH = strings.TrimSpace(in.NameTH)
+	if in.NameTH == "" {
+		return errors.New("name_th is required")
+	}
+	if in.NameEN != nil {
+		if s := strings.TrimSpace(*in.NameEN); s == "" {
+			in.NameEN = nil
+		} else {
+			in.NameEN = &s
+		}
+	}
+	synonyms := []string{}
+	for _, s := range in.Synonyms {
+		if s = strings.TrimSpace(s); s != "" && s != in.NameTH && !containsString(synonyms, s) {
+			synonyms = append(synonyms, s)
+		}
+	}
+	in.Synonyms = synonyms
+	return nil
+}
+
+func tagWriteFailed(c *gin.Context, err error) {
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "duplicate"):
+		c.JSON(409, gin.H{"error": "tag name or synonym already in use"})
+	case strings.Contains(msg, "foreign key"):
+		c.JSON(400, gin.H{"error": "parent tag not found"})
+	default:
+		c.JSON(500, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,42 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestJudgmentWriteErrorUnknownTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantTags   []string
+	}{
+		{&unknownTagsError{tags: []string{"อาญ", "thef"}}, 400, []string{"อาญ", "thef"}},
+		{fmt.Errorf("insert: %w", &unknownTagsError{tags: []string{"x"}}), 400, []string{"x"}},
+		{errors.New("db down"), 500, nil},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		judgmentWriteError(c, tt.err)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%v: status %d, want %d", tt.err, w.Code, tt.wantStatus)
+		}
+		var body struct {
+			UnknownTags []string `json:"unknown_tags"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(body.UnknownTags, tt.wantTags) {
+			t.Errorf("%v: unknown_tags = %q, want %q", tt.err, body.UnknownTags, tt.wantTags)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
+
+	"judgment-notes/cmd/internal/mail"
 )
 
 type AdminCreateUserPayload struct {
@@ -14,6 +16,8 @@ type AdminCreateUserPayload struct {
 	Password string `json:"password"`
 	Name     string `json:"name"`
 	Role     string `json:"role"` // admin/user
+
+	EmailVerified bool `json:"email_verified"` // true = ไม่ต้องส่งอีเมลยืนยัน
 }
 
 type AdminUpdateUserPayload struct {
@@ -21,23 +25,36 @@ type AdminUpdateUserPayload struct {
 	Name     *string `json:"name"`
 	Role     *string `json:"role"`     // admin/user
 	Password *string `json:"password"` // optional reset
+
+	EmailVerified *bool `json:"email_verified"` // เปลี่ยนอีเมลแล้วไม่ส่ง = ต้องยืนยันใหม่
 }
 
 type AdminUser struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	AvatarURL *string   `json:"avatar_url"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	Name          string    `json:"name"`
+	Role          string    `json:"role"`
+	AvatarURL     *string   `json:"avatar_url"`
+	MFAEnabled    bool      `json:"mfa_enabled"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Role struct {
+	Name       string `json:"name"`
+	RequireMFA bool   `json:"require_mfa"` // user ใน role นี้ต้องเปิด 2FA ก่อนใช้งาน
+	UserCount  int    `json:"user_count"`
 }
 
-func registerUserAdminRoutes(api *gin.RouterGroup, pool *pgxpool.Pool) {
+func registerUserAdminRoutes(api *gin.RouterGroup, pool *pgxpool.Pool, mailer mail.Mailer) {
 	api.GET("/users", func(c *gin.Context) { adminListUsers(c, pool) })
 	api.GET("/users/:id", func(c *gin.Context) { adminGetUser(c, pool) })
-	api.POST("/users", func(c *gin.Context) { adminCreateUser(c, pool) })
-	api.PATCH("/users/:id", func(c *gin.Context) { adminUpdateUser(c, pool) })
+	api.POST("/users", func(c *gin.Context) { adminCreateUser(c, pool, mailer) })
+	api.PATCH("/users/:id", func(c *gin.Context) { adminUpdateUser(c, pool, mailer) })
 	api.DELETE("/users/:id", func(c *gin.Context) { adminDeleteUser(c, pool) })
+	api.DELETE("/users/:id/mfa", func(c *gin.Context) { adminResetMFA(c, pool) })
+	api.GET("/roles", func(c *gin.Context) { adminListRoles(c, pool) })
+	api.PATCH("/roles/:name", func(c *gin.Context) { adminUpdateRole(c, pool) })
 }
 
 func normalizeRole(s string) string {
@@ -50,7 +67,7 @@ func isValidRole(r string) bool {
 
 func adminListUsers(c *gin.Context, pool *pgxpool.Pool) {
 	rows, err := pool.Query(c, `
-		SELECT id, email, name, role, avatar_url, created_at
+		SELECT id, email, name, role, avatar_url, totp_enabled_at IS NOT NULL, email_verified_at IS NOT NULL, created_at
 		FROM users
 		ORDER BY created_at DESC
 	`)
@@ -63,7 +80,7 @@ func adminListUsers(c *gin.Context, pool *pgxpool.Pool) {
 	out := make([]AdminUser, 0)
 	for rows.Next() {
 		var u AdminUser
-		if err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.AvatarURL, &u.CreatedAt); err != nil {
+		if err := rows.Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.AvatarURL, &u.MFAEnabled, &u.EmailVerified, &u.CreatedAt); err != nil {
 			c.JSON(500, gin.H{"error": err.Error()})
 			return
 		}
@@ -77,10 +94,10 @@ func adminGetUser(c *gin.Context, pool *pgxpool.Pool) {
 
 	var u AdminUser
 	err := pool.QueryRow(c, `
-		SELECT id, email, name, role, avatar_url, created_at
+		SELECT id, email, name, role, avatar_url, totp_enabled_at IS NOT NULL, email_verified_at IS NOT NULL, created_at
 		FROM users
 		WHERE id=$1
-	`, id).Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.AvatarURL, &u.CreatedAt)
+	`, id).Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.AvatarURL, &u.MFAEnabled, &u.EmailVerified, &u.CreatedAt)
 
 	if err != nil {
 		c.JSON(404, gin.H{"error": "user not found"})
@@ -89,7 +106,7 @@ func adminGetUser(c *gin.Context, pool *pgxpool.Pool) {
 	c.JSON(200, u)
 }
 
-func adminCreateUser(c *gin.Context, pool *pgxpool.Pool) {
+func adminCreateUser(c *gin.Context, pool *pgxpool.Pool, mailer mail.Mailer) {
 	var in AdminCreateUserPayload
 	if err := c.ShouldBindJSON(&in); err != nil {
 		c.JSON(400, gin.H{"error": "invalid payload"})
@@ -122,12 +139,19 @@ func adminCreateUser(c *gin.Context, pool *pgxpool.Pool) {
 		return
 	}
 
+	tx, err := pool.Begin(c)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback(c)
+
 	var u AdminUser
-	err = pool.QueryRow(c, `
-		INSERT INTO users (email, password_hash, name, role)
-		VALUES ($1,$2,$3,$4)
-		RETURNING id, email, name, role, avatar_url, created_at
-	`, email, string(hashed), name, role).Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.AvatarURL, &u.CreatedAt)
+	err = tx.QueryRow(c, `
+		INSERT INTO users (email, password_hash, name, role, email_verified_at)
+		VALUES ($1,$2,$3,$4, CASE WHEN $5 THEN now() END)
+		RETURNING id, email, name, role, avatar_url, email_verified_at IS NOT NULL, created_at
+	`, email, string(hashed), name, role, in.EmailVerified).Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.AvatarURL, &u.EmailVerified, &u.CreatedAt)
 
 	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
@@ -138,10 +162,24 @@ func adminCreateUser(c *gin.Context, pool *pgxpool.Pool) {
 		return
 	}
 
+	// ✅ token ยืนยันอีเมลออกใน tx เดียวกับ user ส่งอีเมลหลัง commit (ส่งไม่ได้แค่ log)
+	var verify *accountEmail
+	if !u.EmailVerified {
+		if verify, err = prepareAccountEmail(c, tx, u.ID, tokenEmailVerify, defaultMailLang, false); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := tx.Commit(c); err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	verify.send(mailer)
+
 	c.JSON(201, u)
 }
 
-func adminUpdateUser(c *gin.Context, pool *pgxpool.Pool) {
+func adminUpdateUser(c *gin.Context, pool *pgxpool.Pool, mailer mail.Mailer) {
 	id := c.Param("id")
 	var in AdminUpdateUserPayload
 	if err := c.ShouldBindJSON(&in); err != nil {
@@ -154,6 +192,7 @@ func adminUpdateUser(c *gin.Context, pool *pgxpool.Pool) {
 	setParts := []string{}
 	args := []any{}
 	argN := 1
+	emailArg := ""
 
 	// ✅ email
 	if in.Email != nil {
@@ -162,11 +201,21 @@ func adminUpdateUser(c *gin.Context, pool *pgxpool.Pool) {
 			c.JSON(400, gin.H{"error": "invalid email"})
 			return
 		}
-		setParts = append(setParts, "email=$"+itoa(argN))
+		emailArg = "$" + itoa(argN)
+		setParts = append(setParts, "email="+emailArg)
 		args = append(args, email)
 		argN++
 	}
 
+	// ✅ admin ยืนยันอีเมลให้ / ยกเลิกการยืนยัน, ไม่ส่งมาแต่เปลี่ยนอีเมล = ต้องยืนยันใหม่
+	if in.EmailVerified != nil {
+		setParts = append(setParts, "email_verified_at=CASE WHEN $"+itoa(argN)+" THEN COALESCE(email_verified_at, now()) END")
+		args = append(args, *in.EmailVerified)
+		argN++
+	} else if emailArg != "" {
+		setParts = append(setParts, "email_verified_at=CASE WHEN email="+emailArg+" THEN email_verified_at END")
+	}
+
 	// ✅ name
 	if in.Name != nil {
 		name := strings.TrimSpace(*in.Name)
@@ -234,6 +283,29 @@ func adminUpdateUser(c *gin.Context, pool *pgxpool.Pool) {
 		return
 	}
 
+	// ✅ อีเมลใหม่ที่ยังไม่ยืนยัน: ส่งลิงก์ยืนยันไปที่อีเมลใหม่
+	if emailArg != "" {
+		var verified bool
+		if err := pool.QueryRow(c, `SELECT email_verified_at IS NOT NULL FROM users WHERE id=$1`, id).Scan(&verified); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		if !verified {
+			if err := sendAccountEmail(c, pool, mailer, id, tokenEmailVerify, defaultMailLang, false); err != nil {
+				c.JSON(500, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	// ✅ ตั้งรหัสผ่านใหม่แล้ว session เดิมทั้งหมดต้อง login ใหม่ และ API key ถูกยกเลิก
+	if in.Password != nil {
+		if _, _, err := revokeUserAccess(c, pool, id); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	c.Status(204)
 }
 
@@ -256,3 +328,50 @@ func adminDeleteUser(c *gin.Context, pool *pgxpool.Pool) {
 	}
 	c.Status(204)
 }
+
+func adminListRoles(c *gin.Context, pool *pgxpool.Pool) {
+	rows, err := pool.Query(c, `
+		SELECT r.name, r.require_mfa, (SELECT COUNT(*) FROM users u WHERE u.role = r.name)
+		FROM roles r
+		ORDER BY r.name
+	`)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	out := make([]Role, 0)
+	for rows.Next() {
+		var r Role
+		if err := rows.Scan(&r.Name, &r.RequireMFA, &r.UserCount); err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		out = append(out, r)
+	}
+	c.JSON(200, out)
+}
+
+// adminUpdateRole: PATCH /roles/:name {"require_mfa": true}
+// user ที่ยังไม่เปิด 2FA จะใช้ได้เฉพาะ route ตั้งค่า 2FA จนกว่าจะเปิด
+func adminUpdateRole(c *gin.Context, pool *pgxpool.Pool) {
+	var in struct {
+		RequireMFA *bool `json:"require_mfa"`
+	}
+	if err := c.ShouldBindJSON(&in); err != nil || in.RequireMFA == nil {
+		c.JSON(400, gin.H{"error": "require_mfa is required"})
+		return
+	}
+
+	var r Role
+	err := pool.QueryRow(c, `
+		UPDATE roles SET require_mfa=$2 WHERE name=$1
+		RETURNING name, require_mfa, (SELECT COUNT(*) FROM users u WHERE u.role = roles.name)
+	`, normalizeRole(c.Param("name")), *in.RequireMFA).Scan(&r.Name, &r.RequireMFA, &r.UserCount)
+	if err != nil {
+		c.JSON(404, gin.H{"error": "role not found"})
+		return
+	}
+	c.JSON(200, r)
+}
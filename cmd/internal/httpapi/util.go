@@ -1,5 +1,26 @@
 package httpapi
 
-import "strconv"
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 func itoa(n int) string { return strconv.Itoa(n) }
+
+// querier ใช้ได้ทั้ง *pgxpool.Pool และ pgx.Tx
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// nullIfEmpty ส่ง NULL แทน string ว่าง (เช่น uuid ที่ไม่มีค่า)
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+// Package importer อ่านไฟล์นำเข้า (CSV, NDJSON, XLSX) เป็นแถวข้อมูลแบบ column → value
+// ส่วนการ map column และ validate อยู่ฝั่งที่เรียกใช้
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"judgment-notes/cmd/internal/office"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	XLSX   Format = "xlsx"
+)
+
+// Row คือข้อมูลหนึ่งแถว; Line คือเลขบรรทัด/แถวในไฟล์ต้นทาง (เริ่มที่ 1) ไว้รายงาน error
+type Row struct {
+	Line   int
+	Values map[string]any
+}
+
+// Get หาค่าตามชื่อ column แบบไม่สนตัวพิมพ์เล็กใหญ่และช่องว่างหัวท้าย
+func (r Row) Get(col string) (any, bool) {
+	if v, ok := r.Values[col]; ok {
+		return v, true
+	}
+	want := normalizeHeader(col)
+	for k, v := range r.Values {
+		if normalizeHeader(k) == want {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func normalizeHeader(s string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(s, "\ufeff")))
+}
+
+// DetectFormat ใช้ format ที่ระบุมา ถ้าไม่ระบุดูจากนามสกุลไฟล์
+func DetectFormat(filename, explicit string) (Format, error) {
+	f := strings.ToLower(strings.TrimSpace(explicit))
+	if f == "" {
+		f = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+	switch f {
+	case "csv":
+		return CSV, nil
+	case "ndjson", "jsonl":
+		return NDJSON, nil
+	case "xlsx":
+		return XLSX, nil
+	}
+	return "", errors.New("unsupported import format (use csv, ndjson or xlsx)")
+}
+
+// Read อ่านทั้งไฟล์เป็นแถว (แถวแรกของ CSV/XLSX คือ header)
+func Read(r io.Reader, format Format) ([]Row, error) {
+	switch format {
+	case CSV:
+		return readCSV(r)
+	case NDJSON:
+		return readNDJSON(r)
+	case XLSX:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		table, err := office.ReadXLSX(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		return tableRows(table), nil
+	}
+	return nil, errors.New("unsupported import format")
+}
+
+func readCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	table, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	return tableRows(table), nil
+}
+
+// tableRows ใช้แถวแรกเป็น header แล้วข้ามแถวที่ว่างทั้งแถว
+func tableRows(table [][]string) []Row {
+	if len(table) == 0 {
+		return nil
+	}
+	header := table[0]
+	out := []Row{}
+	for i, rec := range table[1:] {
+		values := map[string]any{}
+		empty := true
+		for j, h := range header {
+			if strings.TrimSpace(h) == "" {
+				continue
+			}
+			v := ""
+			if j < len(rec) {
+				v = rec[j]
+			}
+			if strings.TrimSpace(v) != "" {
+				empty = false
+			}
+			values[h] = v
+		}
+		if !empty {
+			out = append(out, Row{Line: i + 2, Values: values})
+		}
+	}
+	return out
+}
+
+func readNDJSON(r io.Reader) ([]Row, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	out := []Row{}
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var values map[string]any
+		if err := json.Unmarshal([]byte(text), &values); err != nil || values == nil {
+			return nil, fmt.Errorf("line %d: not a JSON object", line)
+		}
+		out = append(out, Row{Line: line, Values: values})
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,139 @@
+package legal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+// ประเภทเลขคดี
+const (
+	CaseDika        = "dika"        // เลขคำพิพากษาศาลฎีกา เช่น ฎ.1234/2565
+	CaseBlack       = "black"       // คดีหมายเลขดำ
+	CaseRed         = "red"         // คดีหมายเลขแดง
+	CaseUnspecified = "unspecified" // มีแค่เลข/ปี ไม่ระบุประเภท
+)
+
+// CaseNumber คือเลขคดีที่แยกส่วนแล้ว ปีเป็น พ.ศ. 4 หลักเสมอ
+type CaseNumber struct {
+	Type   string
+	Prefix string // อักษรประเภทคดีหน้าเลข เช่น "อ." "พ." (ไม่รวม ฎ.)
+	Number int
+	Year   int
+}
+
+var (
+	caseNoRe = regexp.MustCompile(`^(.*?)((?:[ก-ฮ]{1,3}\.)*)\s*(\d{1,6})\s*/\s*(\d+)$`)
+
+	// คำที่ยอมให้อยู่หน้าเลขคดี (ยาวก่อน)
+	caseNoWords = []string{
+		"คำพิพากษาศาลฎีกา", "คำสั่งศาลฎีกา", "คดีหมายเลขดำ", "คดีหมายเลขแดง",
+		"หมายเลขดำ", "หมายเลขแดง", "คำพิพากษา", "ศาลฎีกา", "เลขที่",
+		"ฎีกา", "คดี", "ดำ", "แดง", "ที่", "ฎ",
+	}
+)
+
+// ParseCaseNo แยกเลขคดีที่พิมพ์ได้หลายแบบ เช่น "ฎ.1234/2565", "1234/65",
+// "คำพิพากษาศาลฎีกาที่ ๑๒๓๔/๒๕๖๕", "คดีหมายเลขดำที่ อ.123/2566"
+// ปี 2 หลักถือเป็น พ.ศ. 25xx, ปี ค.ศ. (1900–2100) แปลงเป็น พ.ศ.
+func ParseCaseNo(s string) (CaseNumber, error) {
+	var n CaseNumber
+	s = strings.Join(strings.Fields(thai.NormalizeDigits(s)), " ")
+	if s == "" {
+		return n, errors.New("empty case number")
+	}
+	m := caseNoRe.FindStringSubmatch(s)
+	if m == nil {
+		return n, errors.New("expected number/year, e.g. 1234/2565")
+	}
+	head, prefix := m[1], m[2]
+
+	// ฎ. ติดกับเลขคือเลขฎีกา ไม่ใช่อักษรประเภทคดี
+	if p, ok := strings.CutPrefix(prefix, "ฎ."); ok {
+		head += "ฎ."
+		prefix = p
+	}
+	n.Prefix = prefix
+
+	rest := head
+	for _, w := range caseNoWords {
+		rest = strings.ReplaceAll(rest, w, "")
+	}
+	if strings.Trim(rest, " .:#") != "" {
+		return n, errors.New("unrecognised text in case number: " + strings.TrimSpace(head))
+	}
+
+	black, red := strings.Contains(head, "ดำ"), strings.Contains(head, "แดง")
+	switch {
+	case black && red:
+		return n, errors.New("case number cannot be both black and red")
+	case black:
+		n.Type = CaseBlack
+	case red:
+		n.Type = CaseRed
+	case strings.Contains(head, "ฎ"):
+		n.Type = CaseDika
+	default:
+		n.Type = CaseUnspecified
+	}
+
+	n.Number, _ = strconv.Atoi(m[3])
+	if n.Number == 0 {
+		return n, errors.New("case number must not be zero")
+	}
+	year, err := normalizeCaseYear(m[4])
+	if err != nil {
+		return n, err
+	}
+	n.Year = year
+	return n, nil
+}
+
+func normalizeCaseYear(s string) (int, error) {
+	y, _ := strconv.Atoi(s)
+	switch {
+	case len(s) == 2:
+		return 2500 + y, nil
+	case len(s) != 4:
+	case y >= minCaseYear && y <= maxCaseYear:
+		return y, nil
+	case y >= 1900 && y <= 2100:
+		return y + thai.BEOffset, nil
+	}
+	return 0, errors.New("invalid year in case number: " + s)
+}
+
+// String คืนรูปมาตรฐาน เช่น "ฎ.1234/2565", "ดำ อ.123/2566", "1234/2565"
+func (n CaseNumber) String() string {
+	num := n.Prefix + strconv.Itoa(n.Number) + "/" + strconv.Itoa(n.Year)
+	switch n.Type {
+	case CaseDika:
+		return "ฎ." + num
+	case CaseBlack:
+		return "ดำ " + num
+	case CaseRed:
+		return "แดง " + num
+	}
+	return num
+}
+
+// Ref คืนเลข/ปี แบบที่ใช้จับคู่ citation
+func (n CaseNumber) Ref() CaseRef {
+	return CaseRef{Number: n.Number, Year: n.Year}
+}
+
+// Variants คือรูปแบบที่ผู้ใช้มักพิมพ์ ใช้ทำ index ให้ค้นเจอไม่ว่าจะพิมพ์แบบไหน
+func (n CaseNumber) Variants() []string {
+	num := strconv.Itoa(n.Number)
+	long := num + "/" + strconv.Itoa(n.Year)
+	short := num + "/" + fmt.Sprintf("%02d", n.Year%100)
+	out := []string{n.String(), long, short}
+	if n.Prefix != "" {
+		out = append(out, n.Prefix+long, n.Prefix+short)
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package legal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCaseNo(t *testing.T) {
+	tests := []struct {
+		in   string
+		want CaseNumber
+	}{
+		{"ฎ.1234/2565", CaseNumber{Type: CaseDika, Number: 1234, Year: 2565}},
+		{"ฎีกาที่ 1234/2565", CaseNumber{Type: CaseDika, Number: 1234, Year: 2565}},
+		{"คำพิพากษาศาลฎีกาที่ ๑๒๓๔/๒๕๖๕", CaseNumber{Type: CaseDika, Number: 1234, Year: 2565}},
+		{"1234/65", CaseNumber{Type: CaseUnspecified, Number: 1234, Year: 2565}},
+		{"1234 / 2565", CaseNumber{Type: CaseUnspecified, Number: 1234, Year: 2565}},
+		{"๑๒/๐๙", CaseNumber{Type: CaseUnspecified, Number: 12, Year: 2509}},
+		// ปี ค.ศ. แปลงเป็น พ.ศ.
+		{"1234/2022", CaseNumber{Type: CaseUnspecified, Number: 1234, Year: 2565}},
+		{"คดีหมายเลขดำที่ อ.123/2566", CaseNumber{Type: CaseBlack, Prefix: "อ.", Number: 123, Year: 2566}},
+		{"หมายเลขแดงที่ พ.๔๕/๒๕๖๗", CaseNumber{Type: CaseRed, Prefix: "พ.", Number: 45, Year: 2567}},
+		{"ดำ ผบ.7/2560", CaseNumber{Type: CaseBlack, Prefix: "ผบ.", Number: 7, Year: 2560}},
+	}
+	for _, tt := range tests {
+		got, err := ParseCaseNo(tt.in)
+		if err != nil {
+			t.Errorf("ParseCaseNo(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCaseNo(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseCaseNoInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"1234",
+		"0/2565",
+		"1234/256",
+		"1234/1800",
+		"ดำ แดง 1/2565",
+		"เรื่องอื่น 1234/2565",
+	} {
+		if got, err := ParseCaseNo(in); err == nil {
+			t.Errorf("ParseCaseNo(%q) = %+v, want error", in, got)
+		}
+	}
+}
+
+func TestCaseNumberStringAndVariants(t *testing.T) {
+	n := CaseNumber{Type: CaseBlack, Prefix: "อ.", Number: 123, Year: 2566}
+	if got := n.String(); got != "ดำ อ.123/2566" {
+		t.Errorf("String() = %q", got)
+	}
+	want := []string{"ดำ อ.123/2566", "123/2566", "123/66", "อ.123/2566", "อ.123/66"}
+	if got := n.Variants(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Variants() = %q, want %q", got, want)
+	}
+	// รูปมาตรฐานต้องแยกกลับได้ค่าเดิม
+	for _, n := range []CaseNumber{
+		n,
+		{Type: CaseDika, Number: 1234, Year: 2565},
+		{Type: CaseRed, Number: 9, Year: 2501},
+		{Type: CaseUnspecified, Number: 77, Year: 2499},
+	} {
+		back, err := ParseCaseNo(n.String())
+		if err != nil || back != n {
+			t.Errorf("round trip %q = %+v, %v", n.String(), back, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package legal รวมเครื่องมือเกี่ยวกับเอกสารกฎหมายไทย เช่น หาเลขคดีที่อ้างถึงในข้อความ
+package legal
+
+import (
+	"strconv"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+// ช่วงปี พ.ศ. ที่ถือว่าเป็นเลขคดี (กันเลขอื่นที่มี / เช่น อัตราส่วน)
+const (
+	minCaseYear = 2400
+	maxCaseYear = 2700
+)
+
+// CaseRef คือเลขคดีแบบ "เลข/ปี พ.ศ." เช่น 1234/2565
+type CaseRef struct {
+	Number int
+	Year   int
+}
+
+// String ตรงกับ case_ref() ใน Postgres
+func (r CaseRef) String() string {
+	return strconv.Itoa(r.Number) + "/" + strconv.Itoa(r.Year)
+}
+
+// FindCaseRefs หาเลขคดีในข้อความ (รับทั้งเลขไทยและอารบิก ช่องว่างรอบ / ได้) ไม่ซ้ำ เรียงตามลำดับที่พบ
+// ไม่นับส่วนของวันที่ เช่น 15/03/2567
+func FindCaseRefs(text string) []CaseRef {
+	rs := []rune(thai.NormalizeDigits(text))
+	isDigit := func(i int) bool { return i >= 0 && i < len(rs) && rs[i] >= '0' && rs[i] <= '9' }
+	skipSpace := func(i, step int) int {
+		for i >= 0 && i < len(rs) && rs[i] == ' ' {
+			i += step
+		}
+		return i
+	}
+
+	seen := map[CaseRef]bool{}
+	out := []CaseRef{}
+	for i, r := range rs {
+		if r != '/' {
+			continue
+		}
+		// ตัวเลขหน้า /
+		end := skipSpace(i-1, -1)
+		start := end
+		for isDigit(start - 1) {
+			start--
+		}
+		if !isDigit(end) || end-start >= 6 || start > 0 && rs[start-1] == '/' {
+			continue
+		}
+		// ปี 4 หลักหลัง /
+		ys := skipSpace(i+1, 1)
+		ye := ys
+		for isDigit(ye) {
+			ye++
+		}
+		if ye-ys != 4 || ye < len(rs) && rs[ye] == '/' {
+			continue
+		}
+
+		num, _ := strconv.Atoi(string(rs[start : end+1]))
+		year, _ := strconv.Atoi(string(rs[ys:ye]))
+		if num == 0 || year < minCaseYear || year > maxCaseYear {
+			continue
+		}
+		ref := CaseRef{Number: num, Year: year}
+		if !seen[ref] {
+			seen[ref] = true
+			out = append(out, ref)
+		}
+	}
+	return out
+}
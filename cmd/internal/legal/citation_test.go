@@ -0,0 +1,40 @@
+package legal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindCaseRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []CaseRef
+	}{
+		{"plain", "ตามคำพิพากษาศาลฎีกาที่ 1234/2565", []CaseRef{{1234, 2565}}},
+		{"spaces around slash", "ฎีกาที่ 1234 / 2565", []CaseRef{{1234, 2565}}},
+		{"thai digits", "ฎ.๑๒๓๔/๒๕๖๕", []CaseRef{{1234, 2565}}},
+		{"zero padded", "ฎีกาที่ 0123/2565", []CaseRef{{123, 2565}}},
+		{"several in order, no duplicates", "ฎ.5/2560 ฎ.1234/2565 และ ๕/๒๕๖๐",
+			[]CaseRef{{5, 2560}, {1234, 2565}}},
+		{"date is not a case", "ลงวันที่ 15/03/2567", []CaseRef{}},
+		{"two digit year", "ฎ.1234/65", []CaseRef{}},
+		{"number too long", "1234567/2565", []CaseRef{}},
+		{"zero number", "0/2565", []CaseRef{}},
+		{"year out of range", "12/1999", []CaseRef{}},
+		{"no slash", "มาตรา 1234 ปี 2565", []CaseRef{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindCaseRefs(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("FindCaseRefs(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaseRefString(t *testing.T) {
+	if got := (CaseRef{Number: 1234, Year: 2565}).String(); got != "1234/2565" {
+		t.Fatalf("String() = %q", got)
+	}
+}
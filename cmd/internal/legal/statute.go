@@ -0,0 +1,141 @@
+package legal
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+// Statute คือประมวลกฎหมายที่ระบบรู้จัก (ต้องตรงกับตาราง statutes)
+type Statute struct {
+	Code    string   // รหัสที่ใช้ใน API/DB
+	Abbr    string   // ชื่อย่อที่แสดง
+	Name    string   // ชื่อเต็ม
+	Aliases []string // คำที่ใช้เรียกในข้อความ (นอกจาก Abbr และ Name)
+}
+
+var Statutes = []Statute{
+	{Code: "civil", Abbr: "ป.พ.พ.", Name: "ประมวลกฎหมายแพ่งและพาณิชย์", Aliases: []string{"ป.พ.พ", "ปพพ."}},
+	{Code: "criminal", Abbr: "ป.อ.", Name: "ประมวลกฎหมายอาญา", Aliases: []string{"ป.อ"}},
+	{Code: "civil-procedure", Abbr: "ป.วิ.พ.", Name: "ประมวลกฎหมายวิธีพิจารณาความแพ่ง", Aliases: []string{"ป.วิ.พ", "ป.วิ.แพ่ง"}},
+	{Code: "criminal-procedure", Abbr: "ป.วิ.อ.", Name: "ประมวลกฎหมายวิธีพิจารณาความอาญา", Aliases: []string{"ป.วิ.อ", "ป.วิ.อาญา"}},
+	{Code: "revenue", Abbr: "ป.รัษฎากร", Name: "ประมวลรัษฎากร"},
+	{Code: "land", Abbr: "ป.ที่ดิน", Name: "ประมวลกฎหมายที่ดิน"},
+	{Code: "constitution", Abbr: "รัฐธรรมนูญ", Name: "รัฐธรรมนูญแห่งราชอาณาจักรไทย"},
+}
+
+// SectionRef คือมาตราของประมวลกฎหมาย เช่น {civil 420} หรือ {civil-procedure 188/1}
+type SectionRef struct {
+	Code    string
+	Section string
+}
+
+var (
+	statuteByAlias = map[string]string{}
+	sectionRe      *regexp.Regexp
+	sectionNoRe    = regexp.MustCompile(`\d+(?:/\d+)?`)
+	// มาตราเดียวหรือช่วง เช่น "420", "188/1", "420 ถึง 425", "420-425"
+	sectionItemRe = regexp.MustCompile(`(\d+(?:/\d+)?)(?:\s*(?:-|ถึง)\s*(?:มาตรา\s*)?(\d+(?:/\d+)?))?`)
+)
+
+// ช่วงมาตราที่กว้างกว่านี้นับแค่มาตราต้นและท้าย (กันข้อความแปลก ๆ กลายเป็นมาตรานับพัน)
+const maxSectionRange = 50
+
+func init() {
+	aliases := []string{}
+	for _, s := range Statutes {
+		for _, a := range append([]string{s.Abbr, s.Name}, s.Aliases...) {
+			statuteByAlias[a] = s.Code
+			aliases = append(aliases, a)
+		}
+	}
+	// ชื่อยาวก่อน (alternation ของ regexp เลือกตัวแรกที่ตรง)
+	sort.Slice(aliases, func(i, j int) bool { return len(aliases[i]) > len(aliases[j]) })
+	for i, a := range aliases {
+		aliases[i] = regexp.QuoteMeta(a)
+	}
+
+	item := `\d+(?:/\d+)?(?:\s*(?:-|ถึง)\s*(?:มาตรา\s*)?\d+(?:/\d+)?)?`
+	list := item + `(?:\s*(?:,|และ|หรือ)\s*(?:มาตรา\s*)?` + item + `)*`
+	// 1: ชื่อประมวล (ตามด้วย มาตรา/ม. ได้)  2: มาตราหลังชื่อ  3: "มาตรา ..." ที่อ้างประมวลตัวก่อนหน้า
+	sectionRe = regexp.MustCompile(`(` + strings.Join(aliases, "|") + `)(?:\s*(?:มาตรา|ม\.)\s*(` + list + `))?|มาตรา\s*(` + list + `)`)
+}
+
+// StatuteByCode คืนข้อมูลประมวลจากรหัส
+func StatuteByCode(code string) (Statute, bool) {
+	for _, s := range Statutes {
+		if s.Code == code {
+			return s, true
+		}
+	}
+	return Statute{}, false
+}
+
+// FindSections หามาตราที่อ้างในข้อความ เช่น "ป.พ.พ. มาตรา 420 และ 438" หรือ "ป.อ.ม.288"
+// ช่วง "มาตรา 420 ถึง 425" นับทุกมาตราในช่วง
+// "มาตรา ..." ที่ไม่ระบุชื่อประมวลนับเป็นของประมวลที่อ้างล่าสุดในข้อความเดียวกัน (ไม่มีก็ข้าม)
+// ไม่ซ้ำ เรียงตามลำดับที่พบ
+func FindSections(texts ...string) []SectionRef {
+	seen := map[SectionRef]bool{}
+	out := []SectionRef{}
+	for _, text := range texts {
+		current := ""
+		for _, m := range sectionRe.FindAllStringSubmatch(thai.NormalizeDigits(text), -1) {
+			list := m[3]
+			if m[1] != "" {
+				current = statuteByAlias[m[1]]
+				list = m[2]
+			}
+			if current == "" {
+				continue
+			}
+			for _, item := range sectionItemRe.FindAllStringSubmatch(list, -1) {
+				for _, no := range expandSections(item[1], item[2]) {
+					ref := SectionRef{Code: current, Section: NormalizeSection(no)}
+					if ref.Section != "" && !seen[ref] {
+						seen[ref] = true
+						out = append(out, ref)
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// expandSections แตกช่วง from-to เป็นทุกมาตรา (เฉพาะเลขมาตราธรรมดา ไม่มี /)
+func expandSections(from, to string) []string {
+	if to == "" {
+		return []string{from}
+	}
+	lo, err1 := strconv.Atoi(from)
+	hi, err2 := strconv.Atoi(to)
+	if err1 != nil || err2 != nil || hi <= lo || hi-lo > maxSectionRange {
+		return []string{from, to}
+	}
+	out := make([]string, 0, hi-lo+1)
+	for n := lo; n <= hi; n++ {
+		out = append(out, strconv.Itoa(n))
+	}
+	return out
+}
+
+// NormalizeSection ทำเลขมาตราให้อยู่รูปเดียว (เลขอารบิก ไม่มีช่องว่าง/เลข 0 นำหน้า) คืน "" ถ้าไม่ใช่เลขมาตรา
+func NormalizeSection(s string) string {
+	s = strings.ReplaceAll(thai.NormalizeDigits(strings.TrimSpace(s)), " ", "")
+	if !sectionNoRe.MatchString(s) || sectionNoRe.FindString(s) != s {
+		return ""
+	}
+	parts := strings.Split(s, "/")
+	for i, p := range parts {
+		p = strings.TrimLeft(p, "0")
+		if p == "" {
+			return ""
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,67 @@
+package legal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindSections(t *testing.T) {
+	civil := func(secs ...string) []SectionRef {
+		out := []SectionRef{}
+		for _, s := range secs {
+			out = append(out, SectionRef{Code: "civil", Section: s})
+		}
+		return out
+	}
+	tests := []struct {
+		name string
+		in   []string
+		want []SectionRef
+	}{
+		{"abbreviation", []string{"ป.พ.พ. มาตรา 420"}, civil("420")},
+		{"abbreviation without last dot", []string{"ป.พ.พ มาตรา 420"}, civil("420")},
+		{"abbreviation without dots", []string{"ปพพ. ม.420"}, civil("420")},
+		{"full name", []string{"ประมวลกฎหมายแพ่งและพาณิชย์ มาตรา 420"}, civil("420")},
+		{"thai digits", []string{"ป.พ.พ. มาตรา ๔๒๐"}, civil("420")},
+		{"paragraph is ignored", []string{"ป.พ.พ. มาตรา ๑๒ วรรคสอง"}, civil("12")},
+		{"list", []string{"ป.พ.พ. มาตรา 420, 438 และมาตรา 1336"}, civil("420", "438", "1336")},
+		{"sub-section", []string{"ป.วิ.พ. มาตรา 188/1"}, []SectionRef{{"civil-procedure", "188/1"}}},
+		{"range", []string{"ป.พ.พ. มาตรา 420 ถึง 423"}, civil("420", "421", "422", "423")},
+		{"range with dash", []string{"ป.พ.พ. มาตรา 420-422"}, civil("420", "421", "422")},
+		{"range repeating มาตรา", []string{"ป.พ.พ. มาตรา 1 ถึงมาตรา 3"}, civil("1", "2", "3")},
+		{"too wide range keeps ends", []string{"ป.พ.พ. มาตรา 1 ถึง 1000"}, civil("1", "1000")},
+		{"bare มาตรา follows last statute", []string{"ป.อ.ม.288 ประกอบมาตรา 80"},
+			[]SectionRef{{"criminal", "288"}, {"criminal", "80"}}},
+		{"bare มาตรา without statute is skipped", []string{"มาตรา 420"}, []SectionRef{}},
+		{"statute does not carry across texts", []string{"ป.พ.พ. มาตรา 420", "มาตรา 421"}, civil("420")},
+		{"no duplicates", []string{"ป.พ.พ. มาตรา 420", "ป.พ.พ. ม.๔๒๐"}, civil("420")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindSections(tt.in...); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("FindSections(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSection(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"420", "420"},
+		{" 0420 ", "420"},
+		{"๔๒๐", "420"},
+		{"188/1", "188/1"},
+		{"๑๘๘/๐๑", "188/1"},
+		{"188 / 1", "188/1"},
+		{"", ""},
+		{"0", ""},
+		{"188/0", ""},
+		{"420ก", ""},
+		{"มาตรา 420", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeSection(tt.in); got != tt.want {
+			t.Errorf("NormalizeSection(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
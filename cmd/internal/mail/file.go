@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File เขียนอีเมลแต่ละฉบับเป็นไฟล์ .eml (ใช้ตอน dev / ทดสอบ)
+type File struct {
+	dir  string
+	from string
+}
+
+func NewFile(dir, from string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir, from: from}, nil
+}
+
+func (f *File) Send(ctx context.Context, m Message) error {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	name := time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(b) + ".eml"
+	return os.WriteFile(filepath.Join(f.dir, name), render(f.from, m), 0o640)
+}
+
+// Log พิมพ์อีเมลลง log ของ server
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (l *Log) Send(ctx context.Context, m Message) error {
+	log.Printf("mail to %s: %s\n%s", m.To, m.Subject, m.Body)
+	return nil
+}
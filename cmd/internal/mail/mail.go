@@ -0,0 +1,51 @@
+// Package mail ส่งอีเมลของระบบ (reset รหัสผ่าน, ยืนยันอีเมล) มีทั้ง SMTP และแบบเขียนไฟล์/log สำหรับ dev
+package mail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+)
+
+// Message คืออีเมลข้อความล้วน 1 ฉบับ
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer ส่งอีเมล
+type Mailer interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// FromEnv เลือก backend ตาม MAIL_BACKEND (log | file | smtp)
+//
+//	log:  พิมพ์อีเมลลง log (ค่าเริ่มต้น)
+//	file: เขียนไฟล์ .eml ลง MAIL_DIR (ค่าเริ่มต้น ./data/mail)
+//	smtp: SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, MAIL_FROM
+func FromEnv() (Mailer, error) {
+	switch strings.ToLower(getEnv("MAIL_BACKEND", "log")) {
+	case "log":
+		return NewLog(), nil
+	case "file":
+		return NewFile(getEnv("MAIL_DIR", "./data/mail"), getEnv("MAIL_FROM", "no-reply@localhost"))
+	case "smtp":
+		return NewSMTP(SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		})
+	}
+	return nil, errors.New("MAIL_BACKEND must be log, file or smtp")
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string // ว่าง = ไม่ต้อง login
+	Password string
+	From     string
+}
+
+// SMTP ส่งผ่าน SMTP server (ใช้ STARTTLS อัตโนมัติถ้า server รองรับ)
+type SMTP struct {
+	cfg SMTPConfig
+}
+
+func NewSMTP(cfg SMTPConfig) (*SMTP, error) {
+	if cfg.Host == "" || cfg.From == "" {
+		return nil, errors.New("mail: SMTP_HOST and MAIL_FROM are required")
+	}
+	return &SMTP{cfg: cfg}, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, m Message) error {
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	// net/smtp ไม่รับ context: ตัดด้วย goroutine แทน
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.cfg.Host, s.cfg.Port), auth, s.cfg.From, []string{m.To}, render(s.cfg.From, m))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// กัน header injection จากค่าที่มาจากผู้ใช้
+var headerSafe = strings.NewReplacer("\r", "", "\n", "")
+
+// render สร้างอีเมลแบบ RFC 5322 (หัวเรื่องภาษาไทยเข้ารหัส UTF-8)
+func render(from string, m Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", headerSafe.Replace(m.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return b.Bytes()
+}
@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// ภาษาของอีเมล
+const (
+	Thai    = "th"
+	English = "en"
+)
+
+// Data คือค่าที่ใช้ใน template
+type Data struct {
+	Name    string
+	Link    string
+	Minutes int // อายุของลิงก์
+}
+
+type tmpl struct {
+	subject string
+	body    *template.Template
+}
+
+var templates = map[string]map[string]tmpl{
+	"password_reset": {
+		Thai: {
+			subject: "ตั้งรหัสผ่านใหม่",
+			body: parse(`เรียน {{.Name}}
+
+มีการขอตั้งรหัสผ่านใหม่สำหรับบัญชีของคุณ กดลิงก์ด้านล่างเพื่อตั้งรหัสผ่านใหม่ (ใช้ได้ {{.Minutes}} นาที และใช้ได้ครั้งเดียว)
+
+{{.Link}}
+
+หากคุณไม่ได้เป็นผู้ขอ ไม่ต้องดำเนินการใด ๆ รหัสผ่านเดิมยังใช้ได้ตามปกติ
+`),
+		},
+		English: {
+			subject: "Reset your password",
+			body: parse(`Hello {{.Name}},
+
+We received a request to reset the password for your account. Use the link below to choose a new password (valid for {{.Minutes}} minutes, single use).
+
+{{.Link}}
+
+If you did not request this, you can ignore this email. Your current password will keep working.
+`),
+		},
+	},
+	"email_verify": {
+		Thai: {
+			subject: "ยืนยันอีเมลของคุณ",
+			body: parse(`เรียน {{.Name}}
+
+กรุณากดลิงก์ด้านล่างเพื่อยืนยันอีเมลของบัญชีนี้ (ใช้ได้ {{.Minutes}} นาที)
+
+{{.Link}}
+
+หากคุณไม่ได้สมัครใช้งาน ไม่ต้องดำเนินการใด ๆ
+`),
+		},
+		English: {
+			subject: "Verify your email address",
+			body: parse(`Hello {{.Name}},
+
+Please confirm the email address for your account using the link below (valid for {{.Minutes}} minutes).
+
+{{.Link}}
+
+If you did not sign up, you can ignore this email.
+`),
+		},
+	},
+}
+
+func parse(s string) *template.Template {
+	return template.Must(template.New("").Parse(s))
+}
+
+// Lang แปลงค่าภาษา (เช่น "en-US", "th") เป็นภาษาที่มี template ไม่รู้จักใช้ fallback
+func Lang(s, fallback string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, l := range []string{Thai, English} {
+		if strings.HasPrefix(s, l) {
+			return l
+		}
+	}
+	return fallback
+}
+
+// Render สร้างอีเมลจาก template (name: password_reset | email_verify)
+func Render(name, lang, to string, d Data) (Message, error) {
+	byLang, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("mail: unknown template %q", name)
+	}
+	t, ok := byLang[lang]
+	if !ok {
+		t = byLang[Thai]
+	}
+	var b bytes.Buffer
+	if err := t.body.Execute(&b, d); err != nil {
+		return Message{}, err
+	}
+	return Message{To: to, Subject: t.subject, Body: b.String()}, nil
+}
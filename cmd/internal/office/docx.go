@@ -0,0 +1,161 @@
+package office
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Docx เขียนไฟล์ docx แบบ stream: เนื้อหาถูกเขียนลง zip ทันทีทีละย่อหน้า
+// ไม่ต้องเก็บทั้งเอกสารไว้ในหน่วยความจำ ต้องเรียก Close เมื่อเขียนเสร็จ
+type Docx struct {
+	zw  *zip.Writer
+	w   *bufio.Writer
+	err error
+}
+
+// ฟอนต์เริ่มต้นของเอกสาร (Word บน Windows/macOS มีทั้งคู่)
+const (
+	docxFont     = "Tahoma"
+	docxThaiFont = "TH Sarabun New"
+)
+
+func NewDocx(w io.Writer) (*Docx, error) {
+	zw := zip.NewWriter(w)
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, xml.Header+p.body); err != nil {
+			return nil, err
+		}
+	}
+
+	// word/document.xml เป็น entry สุดท้าย เขียนต่อไปเรื่อย ๆ จนถึง Close
+	f, err := zw.Create("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	d := &Docx{zw: zw, w: bufio.NewWriter(f)}
+	d.write(xml.Header + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`)
+	return d, d.err
+}
+
+// Heading level 1-2 (ใช้ style Heading1/Heading2 ให้ Word สร้างสารบัญได้)
+func (d *Docx) Heading(level int, text string) error {
+	if level < 1 {
+		level = 1
+	}
+	if level > 2 {
+		level = 2
+	}
+	d.write(`<w:p><w:pPr><w:pStyle w:val="Heading` + string(rune('0'+level)) + `"/></w:pPr>`)
+	d.run(text, false)
+	d.write(`</w:p>`)
+	return d.err
+}
+
+// Paragraph เขียนข้อความ แต่ละบรรทัด (\n) เป็นย่อหน้าแยกกัน
+func (d *Docx) Paragraph(text string) error {
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		d.write(`<w:p>`)
+		d.run(line, false)
+		d.write(`</w:p>`)
+	}
+	return d.err
+}
+
+// Field เขียน "label: value" โดย label เป็นตัวหนา
+func (d *Docx) Field(label, value string) error {
+	d.write(`<w:p>`)
+	d.run(label+": ", true)
+	d.run(value, false)
+	d.write(`</w:p>`)
+	return d.err
+}
+
+func (d *Docx) PageBreak() error {
+	d.write(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+	return d.err
+}
+
+// Close ปิด document.xml และ zip (ไม่ปิด writer ปลายทาง)
+func (d *Docx) Close() error {
+	d.write(`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/>` +
+		`<w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/>` +
+		`</w:sectPr></w:body></w:document>`)
+	if d.err == nil {
+		d.err = d.w.Flush()
+	}
+	if err := d.zw.Close(); d.err == nil {
+		d.err = err
+	}
+	return d.err
+}
+
+func (d *Docx) run(text string, bold bool) {
+	d.write(`<w:r>`)
+	if bold {
+		d.write(`<w:rPr><w:b/><w:bCs/></w:rPr>`)
+	}
+	d.write(`<w:t xml:space="preserve">`)
+	if d.err == nil {
+		d.err = xml.EscapeText(d.w, []byte(stripInvalidXML(text)))
+	}
+	d.write(`</w:t></w:r>`)
+}
+
+func (d *Docx) write(s string) {
+	if d.err == nil {
+		_, d.err = d.w.WriteString(s)
+	}
+}
+
+// stripInvalidXML ตัด control character ที่ XML 1.0 ไม่อนุญาต (Word จะเปิดไฟล์ไม่ได้)
+func stripInvalidXML(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || r >= 0x20 && r != 0xFFFE && r != 0xFFFF {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+const docxContentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`</Types>`
+
+const docxRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+const docxDocumentRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+const docxStyles = `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr>` +
+	`<w:rFonts w:ascii="` + docxFont + `" w:hAnsi="` + docxFont + `" w:cs="` + docxThaiFont + `"/>` +
+	`<w:sz w:val="22"/><w:szCs w:val="32"/><w:lang w:val="en-US" w:bidi="th-TH"/>` +
+	`</w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="120"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr>` +
+	`<w:rPr><w:b/><w:bCs/><w:sz w:val="32"/><w:szCs w:val="44"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="1"/></w:pPr>` +
+	`<w:rPr><w:b/><w:bCs/><w:sz w:val="26"/><w:szCs w:val="36"/></w:rPr></w:style>` +
+	`</w:styles>`
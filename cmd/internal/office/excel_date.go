@@ -0,0 +1,18 @@
+package office
+
+import (
+	"strconv"
+	"time"
+)
+
+// ExcelSerialDate แปลง serial number ของ Excel (ระบบ 1900) เป็นวันที่
+// เช่น "45366" → 2024-03-15; คืน false ถ้าไม่ใช่ตัวเลขในช่วงที่สมเหตุสมผล
+func ExcelSerialDate(s string) (time.Time, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 1 || f > 2958465 { // 9999-12-31
+		return time.Time{}, false
+	}
+	// Excel นับ 1900-02-29 ที่ไม่มีอยู่จริง จึงเริ่มนับจาก 1899-12-30
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return base.AddDate(0, 0, int(f)), true
+}
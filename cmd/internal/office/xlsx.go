@@ -0,0 +1,203 @@
+// Package office อ่าน/เขียนไฟล์ Office Open XML (xlsx, docx) แบบง่าย ๆ ด้วย standard library
+package office
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ReadXLSX อ่าน sheet แรกของไฟล์ xlsx คืนเป็นแถวของข้อความ (ช่องว่างท้ายแถวถูกตัดออก)
+// ตัวเลขคืนตามที่เก็บในไฟล์ (วันที่จะเป็น serial number ของ Excel ดู ExcelSerialDate)
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := readSharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, errors.New("xlsx: worksheet not found")
+	}
+	return readSheet(f, shared)
+}
+
+func decodeXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// firstSheetPath หา path ของ sheet แรกจาก workbook.xml + relationships
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	wb, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("xlsx: workbook.xml not found")
+	}
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeXML(wb, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx: workbook has no sheets")
+	}
+
+	rels, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	var relationships struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeXML(rels, &relationships); err != nil {
+		return "", err
+	}
+	for _, rel := range relationships.Rels {
+		if rel.ID == workbook.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "", errors.New("xlsx: sheet relationship not found")
+}
+
+// richText คือ <si> / <is>: ข้อความอยู่ใน <t> ตรง ๆ หรือแบ่งเป็นหลาย <r><t>
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt richText) String() string {
+	if len(rt.Runs) == 0 {
+		return rt.T
+	}
+	var b strings.Builder
+	b.WriteString(rt.T)
+	for _, r := range rt.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := decodeXML(f, &sst); err != nil {
+		return nil, err
+	}
+	out := make([]string, len(sst.Items))
+	for i, it := range sst.Items {
+		out[i] = it.String()
+	}
+	return out, nil
+}
+
+func readSheet(f *zip.File, shared []string) ([][]string, error) {
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline richText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeXML(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	out := [][]string{}
+	for i, row := range sheet.Rows {
+		// แถวว่างไม่ถูกเขียนลงไฟล์ เติมให้เลขแถวตรงกับใน Excel
+		rowNo := row.R
+		if rowNo == 0 {
+			rowNo = i + 1
+		}
+		for len(out) < rowNo-1 {
+			out = append(out, nil)
+		}
+
+		cells := []string{}
+		for j, c := range row.Cells {
+			col := j
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(cells) < col {
+				cells = append(cells, "")
+			}
+
+			v := c.Value
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(strings.TrimSpace(v))
+				if err != nil || n < 0 || n >= len(shared) {
+					return nil, errors.New("xlsx: bad shared string index in " + c.Ref)
+				}
+				v = shared[n]
+			case "inlineStr":
+				v = c.Inline.String()
+			case "b":
+				if v == "1" {
+					v = "TRUE"
+				} else {
+					v = "FALSE"
+				}
+			}
+			cells = append(cells, v)
+		}
+		for len(cells) > 0 && cells[len(cells)-1] == "" {
+			cells = cells[:len(cells)-1]
+		}
+		out = append(out, cells)
+	}
+	return out, nil
+}
+
+// columnIndex แปลง "C12" → 2 (เริ่มที่ 0)
+func columnIndex(ref string) int {
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A'+1)
+	}
+	return n - 1
+}
@@ -0,0 +1,262 @@
+// Package search สร้าง tsvector / tsquery สำหรับ full-text search ของ Postgres
+// โดยตัดคำภาษาไทยฝั่ง Go เอง (parser ของ Postgres ตัดคำไทยไม่ได้)
+// และทำ snippet ที่ไฮไลต์คำค้นแบบเดียวกับ ts_headline
+package search
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"judgment-notes/cmd/internal/thai"
+)
+
+// น้ำหนักของ field ตาม setweight ของ Postgres (A สำคัญที่สุด)
+const (
+	WeightA byte = 'A'
+	WeightB byte = 'B'
+	WeightC byte = 'C'
+	WeightD byte = 'D'
+)
+
+// Field คือข้อความหนึ่งก้อนที่จะเอาเข้า index พร้อมน้ำหนัก
+type Field struct {
+	Text   string
+	Weight byte
+}
+
+const (
+	maxPos         = 16383 // ตำแหน่งสูงสุดที่ tsvector รับได้
+	maxPosPerLex   = 256
+	maxLexemeBytes = 200
+)
+
+// Tokens ตัดคำแล้ว normalize (ตัวพิมพ์เล็ก, เลขไทยเป็นเลขอารบิก)
+func Tokens(s string) []string {
+	raw := thai.Segment(thai.NormalizeDigits(s))
+	out := make([]string, 0, len(raw))
+	for _, t := range raw {
+		t = strings.ToLower(t)
+		if t == "" || len(t) > maxLexemeBytes {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// Vector สร้าง tsvector literal (ใช้กับ $n::tsvector) จากหลาย field
+// คำประสมจะถูกแตกเป็นคำย่อยไว้ที่ตำแหน่งเดียวกันด้วย เพื่อให้ค้นคำย่อยแล้วเจอ
+func Vector(fields ...Field) string {
+	type posList []string
+	lex := map[string]posList{}
+	pos := 0
+	add := func(t string, p int, w byte) {
+		if len(lex[t]) >= maxPosPerLex {
+			return
+		}
+		entry := strconv.Itoa(p)
+		if w != WeightD {
+			entry += string(w)
+		}
+		lex[t] = append(lex[t], entry)
+	}
+
+	for _, f := range fields {
+		toks := Tokens(f.Text)
+		if len(toks) == 0 {
+			continue
+		}
+		pos++ // เว้นช่องระหว่าง field กันไม่ให้ phrase ข้าม field
+		for _, t := range toks {
+			if pos < maxPos {
+				pos++
+			}
+			add(t, pos, f.Weight)
+			for _, sub := range thai.SubWords(t) {
+				add(sub, pos, f.Weight)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(lex))
+	for k := range lex {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(quote(k))
+		b.WriteByte(':')
+		b.WriteString(strings.Join(lex[k], ","))
+	}
+	return b.String()
+}
+
+// Query สร้าง tsquery literal: คำที่คั่นด้วยช่องว่างต้องเจอทุกคำ (&)
+// ส่วนคำไทยที่ตัดออกมาได้หลายคำต้องอยู่ติดกัน (<->)
+// คืน "" ถ้าไม่มีคำให้ค้น
+//
+// ข้อจำกัด: ค้นได้ระดับคำเท่านั้น คำไทยที่ไม่มีในพจนานุกรม (thai/words.txt) ถูกเก็บเป็นคำเดียวทั้งก้อน
+// ค้นด้วยบางส่วนของคำนั้นจึงไม่เจอ (เช่น เก็บ "กขคงจ" ค้น "ขค" ไม่เจอ) ต้องเพิ่มคำลงพจนานุกรม
+func Query(q string) string {
+	groups := []string{}
+	for _, word := range strings.Fields(q) {
+		toks := Tokens(word)
+		if len(toks) == 0 {
+			continue
+		}
+		parts := make([]string, len(toks))
+		for i, t := range toks {
+			parts[i] = quote(t)
+		}
+		g := strings.Join(parts, " <-> ")
+		if len(parts) > 1 {
+			g = "(" + g + ")"
+		}
+		groups = append(groups, g)
+	}
+	return strings.Join(groups, " & ")
+}
+
+// Terms คืนคำที่ใช้ไฮไลต์: ทั้งคำตามที่พิมพ์และคำที่ตัดได้
+func Terms(q string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	add := func(t string) {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	for _, word := range strings.Fields(thai.NormalizeDigits(q)) {
+		add(word)
+		for _, t := range Tokens(word) {
+			add(t)
+		}
+	}
+	// คำยาวก่อน จะได้ไฮไลต์ทั้งคำแทนที่จะได้แค่บางส่วน
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i]) > len(out[j]) })
+	return out
+}
+
+// ตัวคั่นไฮไลต์ (ค่าเดียวกับ ts_headline)
+const (
+	StartSel = "<b>"
+	StopSel  = "</b>"
+)
+
+// Highlight ตัดข้อความรอบ ๆ คำที่เจอครั้งแรก (ประมาณ maxRunes ตัวอักษร)
+// และครอบคำที่ตรงด้วย StartSel/StopSel; คืน false ถ้าไม่เจอคำค้นเลย
+// ผลลัพธ์เป็น HTML: ข้อความเดิมถูก escape แล้ว มีแท็กเฉพาะ StartSel/StopSel
+// หาคำในข้อความที่แปลงเลขไทยแล้ว แต่คืนข้อความเดิม (เลขไทยยังเป็นเลขไทย)
+func Highlight(text string, terms []string, maxRunes int) (string, bool) {
+	if text == "" || len(terms) == 0 {
+		return "", false
+	}
+	norm, pos := normalizeDigitsPos(text)
+	lower := strings.ToLower(norm)
+	if len(lower) != len(norm) {
+		lower = norm // ToLower เปลี่ยนความยาว byte ใช้ตำแหน่งร่วมกันไม่ได้
+	}
+
+	type span struct{ from, to int }
+	spans := []span{}
+	taken := make([]bool, len(lower))
+	for _, t := range terms {
+		for off := 0; ; {
+			i := strings.Index(lower[off:], t)
+			if i < 0 {
+				break
+			}
+			from, to := off+i, off+i+len(t)
+			free := true
+			for k := from; k < to; k++ {
+				if taken[k] {
+					free = false
+					break
+				}
+			}
+			if free {
+				for k := from; k < to; k++ {
+					taken[k] = true
+				}
+				spans = append(spans, span{from, to})
+			}
+			off = to
+		}
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from < spans[j].from })
+
+	// หน้าต่างรอบคำแรกที่เจอ
+	start := spans[0].from
+	for back := maxRunes / 3; back > 0 && start > 0; back-- {
+		_, size := utf8.DecodeLastRuneInString(norm[:start])
+		start -= size
+	}
+	end := start
+	for n := 0; n < maxRunes && end < len(norm); n++ {
+		_, size := utf8.DecodeRuneInString(norm[end:])
+		end += size
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	cur := start
+	for _, s := range spans {
+		if s.from < start || s.to > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos[cur]:pos[s.from]]))
+		b.WriteString(StartSel)
+		b.WriteString(html.EscapeString(text[pos[s.from]:pos[s.to]]))
+		b.WriteString(StopSel)
+		cur = s.to
+	}
+	b.WriteString(html.EscapeString(text[pos[cur]:pos[end]]))
+	if end < len(norm) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+// normalizeDigitsPos แปลงเลขไทยเป็นอารบิกแบบ thai.NormalizeDigits และคืน pos[i] = ตำแหน่ง byte ในข้อความเดิม
+// ของ byte ที่ i ในผลลัพธ์ (len(pos) = len(ผลลัพธ์)+1) เพราะเลขไทย 3 byte กลายเป็น 1 byte
+func normalizeDigitsPos(s string) (string, []int) {
+	var b strings.Builder
+	b.Grow(len(s))
+	pos := make([]int, 0, len(s)+1)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if thai.IsThaiDigit(r) {
+			b.WriteByte(byte('0' + (r - '๐')))
+			pos = append(pos, i)
+		} else {
+			b.WriteString(s[i : i+size])
+			for k := 0; k < size; k++ {
+				pos = append(pos, i+k)
+			}
+		}
+		i += size
+	}
+	return b.String(), append(pos, len(s))
+}
+
+// quote ครอบ lexeme ด้วย ' ตามรูปแบบ input ของ tsvector/tsquery
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `''`)
+	return "'" + s + "'"
+}
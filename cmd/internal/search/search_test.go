@@ -0,0 +1,85 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"ลักทรัพย์", "'ลักทรัพย์'"},
+		{"จำเลยลักทรัพย์", "('จำเลย' <-> 'ลักทรัพย์')"},
+		{"ลักทรัพย์ ๑๒๓๔/๒๕๖๕", "'ลักทรัพย์' & '1234/2565'"},
+		{"Fraud ป.พ.พ.", "'fraud' & 'ป.พ.พ.'"},
+		{"o'brien", "('o' <-> 'brien')"},
+	}
+	for _, tt := range tests {
+		if got := Query(tt.in); got != tt.want {
+			t.Errorf("Query(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVectorSubWords(t *testing.T) {
+	got := Vector(Field{Text: "จำเลยลักทรัพย์", Weight: WeightA})
+	want := "'จำเลย':2A 'ทรัพย์':3A 'ลัก':3A 'ลักทรัพย์':3A"
+	if got != want {
+		t.Errorf("Vector = %q, want %q", got, want)
+	}
+}
+
+// คำที่ไม่อยู่ในพจนานุกรมเป็นคำเดียวทั้งก้อน ค้นด้วยบางส่วนของคำจึงไม่เจอ (ข้อจำกัดที่รู้อยู่ ดู Query)
+func TestUnknownWordIsSingleLexeme(t *testing.T) {
+	const unknown = "ฟฤหฬฮฆ"
+	vec := Vector(Field{Text: "ลักทรัพย์" + unknown, Weight: WeightD})
+	if !strings.Contains(vec, "'"+unknown+"'") {
+		t.Fatalf("Vector = %q, want whole unknown word as one lexeme", vec)
+	}
+	sub := Query("หฬฮ")
+	if strings.Contains(vec, sub) {
+		t.Errorf("substring query %q unexpectedly matches vector %q", sub, vec)
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("จำเลยลักทรัพย์ ๑๒๓๔/๒๕๖๕")
+	want := []string{"จำเลยลักทรัพย์", "ลักทรัพย์", "จำเลย", "1234/2565"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms = %q, want %q", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		terms    []string
+		maxRunes int
+		want     string
+		wantOK   bool
+	}{
+		{"no terms", "abc", nil, 10, "", false},
+		{"no match", "abc", []string{"x"}, 10, "", false},
+		{"simple", "จำเลยลักทรัพย์ของโจทก์", []string{"ลักทรัพย์"}, 100, "จำเลย<b>ลักทรัพย์</b>ของโจทก์", true},
+		{"thai digits", "คดีหมายเลข ๑๒๓/๒๕๖๕", []string{"123/2565"}, 100, "คดีหมายเลข <b>๑๒๓/๒๕๖๕</b>", true},
+		{"mixed digits", "ฎ.๑๒๓/2565 และ 456/๒๕๖๖", []string{"456/2566"}, 100, "ฎ.๑๒๓/2565 และ <b>456/๒๕๖๖</b>", true},
+		{"window around thai digits", "ก่อนหน้า ๑๒๓๔๕ ตรงนี้ ๙", []string{"ตรงนี้"}, 8, "…๕ <b>ตรงนี้</b>…", true},
+		{"case insensitive", "Fraud case", []string{"fraud"}, 100, "<b>Fraud</b> case", true},
+		{"escape", `<script>a & "b"</script>`, []string{"a"}, 100,
+			`&lt;script&gt;<b>a</b> &amp; &#34;b&#34;&lt;/script&gt;`, true},
+		{"escape inside match", "x<y", []string{"x<y"}, 100, "<b>x&lt;y</b>", true},
+		{"window", "0123456789abcdefghij", []string{"k"}, 6, "", false},
+		{"ellipsis", "0123456789abcdefghij", []string{"a"}, 6, "…89<b>a</b>bcd…", true},
+	}
+	for _, tt := range tests {
+		got, ok := Highlight(tt.text, tt.terms, tt.maxRunes)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: Highlight = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
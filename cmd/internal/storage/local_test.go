@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var fakeModTime = time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+func TestLocalPutOpenDelete(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewLocal(filepath.Join(dir, "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := l.Put(ctx, "judgments/1/a.txt", strings.NewReader("hello world"), 11, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := l.Open(ctx, "judgments/1/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(f)
+	f.Close()
+	if string(got) != "world" {
+		t.Errorf("read after seek = %q", got)
+	}
+
+	if err := l.Delete(ctx, "judgments/1/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Open(ctx, "judgments/1/a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after delete = %v, want ErrNotFound", err)
+	}
+	if err := l.Delete(ctx, "judgments/1/a.txt"); err != nil {
+		t.Errorf("second Delete = %v", err)
+	}
+}
+
+func TestLocalPutSizeMismatch(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = l.Put(context.Background(), "a.txt", strings.NewReader("short"), 100, "")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Put = %v, want ErrUnexpectedEOF", err)
+	}
+	// ไม่มีไฟล์ครึ่ง ๆ กลาง ๆ เหลือ
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("leftover files: %v", entries)
+	}
+}
+
+func TestLocalRejectsPathTraversal(t *testing.T) {
+	root := t.TempDir()
+	l, err := NewLocal(filepath.Join(root, "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"", "/", "..", "../escape.txt", "a/../../escape.txt", "a/..", `..\escape.txt`} {
+		if err := l.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) succeeded, want error", key)
+		}
+		if _, err := l.Open(ctx, key); err == nil {
+			t.Errorf("Open(%q) succeeded, want error", key)
+		}
+		if err := l.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) succeeded, want error", key)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file written outside store dir: %v", err)
+	}
+
+	// key ที่ขึ้นต้นด้วย / ยังอยู่ใต้ dir
+	if err := l.Put(ctx, "/abs/x.txt", strings.NewReader("x"), 1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "store", "abs", "x.txt")); err != nil {
+		t.Errorf("absolute-looking key not kept inside store: %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "ap-southeast-1"
+	testBucket    = "attachments"
+)
+
+// fakeS3 เป็นตัวแทน MinIO: เก็บ object ใน memory และตรวจลายเซ็น SigV4 ทุก request
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte // RequestURI path → content
+	types   map[string]string
+	ranges  []string // Range header ของ GET ที่ได้รับ
+
+	allowBadAuth bool // ลายเซ็นผิดเป็นกรณีที่ตั้งใจทดสอบ (ตอบ 403 โดยไม่ fail test)
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{t: t, objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := verifySigV4(r); err != nil {
+		if !f.allowBadAuth {
+			f.t.Errorf("%s %s: %v", r.Method, r.RequestURI, err)
+		}
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := r.URL.EscapedPath()
+	if !strings.HasPrefix(key, "/"+testBucket+"/") {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if int64(len(body)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodHead, http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			return
+		}
+		rng := r.Header.Get("Range")
+		f.ranges = append(f.ranges, rng)
+		if from, ok := strings.CutPrefix(rng, "bytes="); ok {
+			start, err := strconv.Atoi(strings.TrimSuffix(from, "-"))
+			if err != nil || start >= len(body) {
+				http.Error(w, "InvalidRange", http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(len(body)-1)+"/"+strconv.Itoa(len(body)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(body[start:])
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySigV4 คำนวณลายเซ็นใหม่จาก request ที่ server ได้รับตามสเปกของ AWS แล้วเทียบกับ Authorization
+func verifySigV4(r *http.Request) error {
+	auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	if !ok {
+		return errors.New("missing AWS4-HMAC-SHA256 authorization")
+	}
+	fields := map[string]string{}
+	for _, part := range strings.Split(auth, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		fields[k] = v
+	}
+	cred := strings.Split(fields["Credential"], "/")
+	if len(cred) != 5 || cred[0] != testAccessKey || cred[2] != testRegion || cred[3] != "s3" || cred[4] != "aws4_request" {
+		return errors.New("bad credential scope: " + fields["Credential"])
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(amzDate, cred[1]) {
+		return errors.New("X-Amz-Date does not match credential date")
+	}
+	payload := r.Header.Get("X-Amz-Content-Sha256")
+	if payload == "" {
+		return errors.New("missing X-Amz-Content-Sha256")
+	}
+
+	signed := strings.Split(fields["SignedHeaders"], ";")
+	required := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if r.Header.Get("Range") != "" {
+		required = append(required, "range")
+	}
+	for _, h := range required {
+		found := false
+		for _, s := range signed {
+			found = found || s == h
+		}
+		if !found {
+			return errors.New("header not signed: " + h)
+		}
+	}
+	var canonHeaders strings.Builder
+	for _, h := range signed {
+		v := r.Header.Get(h)
+		if h == "host" {
+			v = r.Host
+		}
+		canonHeaders.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	canonical := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" +
+		canonHeaders.String() + "\n" + fields["SignedHeaders"] + "\n" + payload
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + strings.Join(cred[1:], "/") + "\n" + hex.EncodeToString(sum[:])
+
+	mac := func(key []byte, s string) []byte {
+		m := hmac.New(sha256.New, key)
+		m.Write([]byte(s))
+		return m.Sum(nil)
+	}
+	k := mac([]byte("AWS4"+testSecretKey), cred[1])
+	k = mac(k, testRegion)
+	k = mac(k, "s3")
+	k = mac(k, "aws4_request")
+	if want := hex.EncodeToString(mac(k, toSign)); fields["Signature"] != want {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func newTestS3(t *testing.T, endpoint string) *S3 {
+	t.Helper()
+	s, err := NewS3(S3Config{Endpoint: endpoint, Region: testRegion, Bucket: testBucket, AccessKey: testAccessKey, SecretKey: testSecretKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestS3PutGetDelete(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	s := newTestS3(t, srv.URL)
+	ctx := context.Background()
+
+	for _, key := range []string{"judgments/1/a.pdf", "judgments/2/คำพิพากษา ฉบับเต็ม (1).pdf"} {
+		content := "hello world " + key
+		if err := s.Put(ctx, key, strings.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+			t.Fatalf("Put(%q): %v", key, err)
+		}
+		if got := fake.types["/"+testBucket+"/"+uriEncode(key, false)]; got != "application/pdf" {
+			t.Errorf("content type = %q", got)
+		}
+
+		f, err := s.Open(ctx, key)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", key, err)
+		}
+		got, err := io.ReadAll(f)
+		f.Close()
+		if err != nil || string(got) != content {
+			t.Errorf("read %q = %q, %v", key, got, err)
+		}
+
+		if err := s.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete(%q): %v", key, err)
+		}
+		if _, err := s.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Open after delete = %v, want ErrNotFound", err)
+		}
+		// ลบซ้ำไม่ถือเป็น error
+		if err := s.Delete(ctx, key); err != nil {
+			t.Errorf("second Delete(%q): %v", key, err)
+		}
+	}
+}
+
+func TestS3RangeRead(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	s := newTestS3(t, srv.URL)
+	ctx := context.Background()
+	if err := s.Put(ctx, "k", strings.NewReader("hello world"), 11, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := s.Open(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if n, err := f.Seek(-5, io.SeekEnd); err != nil || n != 6 {
+		t.Fatalf("Seek = %d, %v", n, err)
+	}
+	got, err := io.ReadAll(f)
+	if err != nil || string(got) != "world" {
+		t.Errorf("read after seek = %q, %v", got, err)
+	}
+	// Seek กลับต้นไฟล์แล้วอ่านใหม่ได้
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(f, buf); err != nil || string(buf) != "hello" {
+		t.Errorf("read from start = %q, %v", buf, err)
+	}
+	want := []string{"bytes=6-", "bytes=0-"}
+	if strings.Join(fake.ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("GET ranges = %q, want %q", fake.ranges, want)
+	}
+
+	// ผ่าน http.ServeContent แบบที่ downloadAttachment ใช้
+	f2, err := s.Open(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	req := httptest.NewRequest("GET", "/download", nil)
+	req.Header.Set("Range", "bytes=6-")
+	rec := httptest.NewRecorder()
+	http.ServeContent(rec, req, "k.txt", fakeModTime, f2)
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "world" {
+		t.Errorf("ServeContent = %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestS3Errors(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	fake.allowBadAuth = true
+	ctx := context.Background()
+
+	// secret ผิด: server ตอบ 403 ต้องกลายเป็น error (ไม่ใช่ ErrNotFound)
+	bad, err := NewS3(S3Config{Endpoint: srv.URL, Region: testRegion, Bucket: testBucket, AccessKey: testAccessKey, SecretKey: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bad.Put(ctx, "k", strings.NewReader("x"), 1, ""); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Put with bad secret = %v, want error", err)
+	}
+
+	if _, err := NewS3(S3Config{Endpoint: srv.URL, Bucket: testBucket}); err == nil {
+		t.Error("NewS3 without credentials should fail")
+	}
+	if _, err := NewS3(S3Config{Endpoint: "not a url", Bucket: testBucket, AccessKey: "a", SecretKey: "b"}); err == nil {
+		t.Error("NewS3 with invalid endpoint should fail")
+	}
+}
@@ -0,0 +1,49 @@
+// Package storage เก็บตัวไฟล์แนบแยกจากฐานข้อมูล มีทั้งแบบ local filesystem และ S3-compatible (เช่น MinIO)
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+var ErrNotFound = errors.New("storage: object not found")
+
+// Store คือที่เก็บไฟล์ตาม key (เช่น "judgments/<id>/<attachment id>")
+type Store interface {
+	// Put เขียนไฟล์ขนาด size byte (ทับของเดิมถ้ามี)
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open คืน reader ที่ Seek ได้ (ใช้กับ http.ServeContent เพื่อรองรับ Range)
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete ลบไฟล์ ไม่ถือเป็น error ถ้าไม่มีอยู่แล้ว
+	Delete(ctx context.Context, key string) error
+}
+
+// FromEnv เลือก backend ตาม STORAGE_BACKEND (local | s3)
+//
+//	local: STORAGE_DIR (ค่าเริ่มต้น ./data/attachments)
+//	s3:    S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY
+func FromEnv() (Store, error) {
+	switch strings.ToLower(getEnv("STORAGE_BACKEND", "local")) {
+	case "local":
+		return NewLocal(getEnv("STORAGE_DIR", "./data/attachments"))
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    getEnv("S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	}
+	return nil, errors.New("STORAGE_BACKEND must be local or s3")
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,116 @@
+package thai
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BEOffset: ปีพุทธศักราช = ปีคริสต์ศักราช + 543
+const BEOffset = 543
+
+var MonthNames = [12]string{
+	"มกราคม", "กุมภาพันธ์", "มีนาคม", "เมษายน", "พฤษภาคม", "มิถุนายน",
+	"กรกฎาคม", "สิงหาคม", "กันยายน", "ตุลาคม", "พฤศจิกายน", "ธันวาคม",
+}
+
+// FormatDateBE จัดรูปแบบวันที่แบบเอกสารราชการ เช่น "15 มีนาคม พ.ศ. 2567"
+func FormatDateBE(t time.Time) string {
+	return strconv.Itoa(t.Day()) + " " + MonthNames[t.Month()-1] + " พ.ศ. " + strconv.Itoa(t.Year()+BEOffset)
+}
+
+// ชื่อเดือนย่อ เช่น "มี.ค."
+var MonthAbbrs = [12]string{
+	"ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.",
+	"ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค.",
+}
+
+// Calendar คือระบบปีที่ใช้รับ/แสดงวันที่
+type Calendar int
+
+const (
+	Gregorian Calendar = iota // ค.ศ.
+	Buddhist                  // พ.ศ.
+)
+
+// MinBEYear: ปี ค.ศ. ตั้งแต่นี้ไม่ใช่วันที่จริง น่าจะเป็นปี พ.ศ. ที่ส่งมาผิดระบบ
+const MinBEYear = 2400
+
+// ParseCalendar อ่านชื่อระบบปี: be/buddhist/th/พ.ศ. หรือ ce/ad/gregorian/iso/ค.ศ.
+func ParseCalendar(s string) (Calendar, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "be", "buddhist", "th", "พ.ศ.", "พศ":
+		return Buddhist, true
+	case "ce", "ad", "gregorian", "iso", "ค.ศ.", "คศ":
+		return Gregorian, true
+	}
+	return Gregorian, false
+}
+
+func (c Calendar) String() string {
+	if c == Buddhist {
+		return "be"
+	}
+	return "ce"
+}
+
+var (
+	isoDateRe   = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	slashDateRe = regexp.MustCompile(`^(\d{1,2})[/.-](\d{1,2})[/.-](\d{4})$`)
+	textDateRe  = regexp.MustCompile(`^(?:วันที่\s*)?(\d{1,2})\s*(\S+?)\s*(?:(พ\.ศ\.|ค\.ศ\.)\s*)?(\d{4})$`)
+)
+
+// ParseDate อ่านวันที่ได้หลายรูปแบบ (เลขไทยได้): 2567-03-15, 15/03/2567, 15 มีนาคม 2567, 15 มี.ค. พ.ศ. 2567
+// ปีที่เขียน พ.ศ./ค.ศ. กำกับไว้ใช้ตามนั้น ที่เหลือถือเป็นปีของ cal; คืนวันที่แบบ ค.ศ.
+func ParseDate(s string, cal Calendar) (time.Time, error) {
+	s = strings.Join(strings.Fields(NormalizeDigits(s)), " ")
+
+	var year, month, day int
+	era := ""
+	if m := isoDateRe.FindStringSubmatch(s); m != nil {
+		year, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+		day, _ = strconv.Atoi(m[3])
+	} else if m := slashDateRe.FindStringSubmatch(s); m != nil {
+		day, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+		year, _ = strconv.Atoi(m[3])
+	} else if m := textDateRe.FindStringSubmatch(s); m != nil {
+		day, _ = strconv.Atoi(m[1])
+		month = monthNumber(m[2])
+		era = m[3]
+		year, _ = strconv.Atoi(m[4])
+	} else {
+		return time.Time{}, errors.New("unrecognised date: " + s)
+	}
+
+	if era == "พ.ศ." || era == "" && cal == Buddhist {
+		year -= BEOffset
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || t.Day() != day || t.Month() != time.Month(month) {
+		return time.Time{}, errors.New("invalid date: " + s)
+	}
+	return t, nil
+}
+
+func monthNumber(s string) int {
+	for i := range MonthNames {
+		if s == MonthNames[i] || s == MonthAbbrs[i] || s == strings.TrimSuffix(MonthAbbrs[i], ".") {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// FormatISO จัดรูปแบบ YYYY-MM-DD ตามระบบปี เช่น 2567-03-15
+func FormatISO(t time.Time, cal Calendar) string {
+	y := t.Year()
+	if cal == Buddhist {
+		y += BEOffset
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", y, t.Month(), t.Day())
+}
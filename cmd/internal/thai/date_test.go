@@ -0,0 +1,120 @@
+package thai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	date := func(y, m, d int) time.Time { return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		in   string
+		cal  Calendar
+		want time.Time
+	}{
+		{"2024-03-15", Gregorian, date(2024, 3, 15)},
+		{"2567-03-15", Buddhist, date(2024, 3, 15)},
+		{"15/03/2024", Gregorian, date(2024, 3, 15)},
+		{"15.3.2567", Buddhist, date(2024, 3, 15)},
+		{"๒๕๖๗-๐๓-๑๕", Buddhist, date(2024, 3, 15)},
+		{"15 มีนาคม 2567", Buddhist, date(2024, 3, 15)},
+		{"วันที่ ๑๕ มี.ค. ๒๕๖๗", Buddhist, date(2024, 3, 15)},
+		// ปีที่กำกับ พ.ศ./ค.ศ. ไว้ใช้ตามนั้น ไม่สน cal
+		{"15 มี.ค. พ.ศ. 2567", Gregorian, date(2024, 3, 15)},
+		{"15 มีนาคม ค.ศ. 2024", Buddhist, date(2024, 3, 15)},
+		// ไม่เดาจากตัวเลขปีแล้ว: calendar=ce ก็เป็น ค.ศ. ตรง ๆ
+		{"2567-03-15", Gregorian, date(2567, 3, 15)},
+		{"2024-03-15", Buddhist, date(1481, 3, 15)},
+		// ขอบปี พ.ศ./ค.ศ.
+		{"1 มกราคม 2400", Buddhist, date(1857, 1, 1)},
+		{"31/12/2399", Gregorian, date(2399, 12, 31)},
+		// 29 ก.พ. ของปีอธิกสุรทิน (พ.ศ. 2567 = ค.ศ. 2024, พ.ศ. 2543 = ค.ศ. 2000)
+		{"2567-02-29", Buddhist, date(2024, 2, 29)},
+		{"29 กุมภาพันธ์ พ.ศ. 2543", Gregorian, date(2000, 2, 29)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in, tt.cal)
+		if err != nil {
+			t.Errorf("ParseDate(%q, %v): %v", tt.in, tt.cal, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q, %v) = %s, want %s", tt.in, tt.cal, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	tests := []struct {
+		in  string
+		cal Calendar
+	}{
+		{"", Gregorian},
+		{"yesterday", Gregorian},
+		{"2024-13-01", Gregorian},
+		{"15 ม.ค.ค. 2567", Buddhist},
+		// พ.ศ. 2566 = ค.ศ. 2023 ไม่ใช่ปีอธิกสุรทิน
+		{"2566-02-29", Buddhist},
+		// ค.ศ. 2567 ไม่ใช่ปีอธิกสุรทิน (ต้องไม่ถูกตีความเป็น พ.ศ. เอง)
+		{"2567-02-29", Gregorian},
+		// พ.ศ. 2443 = ค.ศ. 1900 ไม่ใช่ปีอธิกสุรทิน
+		{"29 ก.พ. พ.ศ. 2443", Gregorian},
+	}
+	for _, tt := range tests {
+		if got, err := ParseDate(tt.in, tt.cal); err == nil {
+			t.Errorf("ParseDate(%q, %v) = %s, want error", tt.in, tt.cal, got.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestFormatDateBE(t *testing.T) {
+	tests := []struct {
+		in   time.Time
+		want string
+	}{
+		{time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), "15 มีนาคม พ.ศ. 2567"},
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "29 กุมภาพันธ์ พ.ศ. 2567"},
+		{time.Date(1857, 1, 1, 0, 0, 0, 0, time.UTC), "1 มกราคม พ.ศ. 2400"},
+		{time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), "31 ธันวาคม พ.ศ. 2566"},
+	}
+	for _, tt := range tests {
+		if got := FormatDateBE(tt.in); got != tt.want {
+			t.Errorf("FormatDateBE(%s) = %q, want %q", tt.in.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestFormatISO(t *testing.T) {
+	d := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	if got := FormatISO(d, Gregorian); got != "2024-02-29" {
+		t.Errorf("FormatISO(ce) = %q", got)
+	}
+	if got := FormatISO(d, Buddhist); got != "2567-02-29" {
+		t.Errorf("FormatISO(be) = %q", got)
+	}
+	// ไปกลับได้
+	back, err := ParseDate(FormatISO(d, Buddhist), Buddhist)
+	if err != nil || !back.Equal(d) {
+		t.Errorf("round trip = %v, %v", back, err)
+	}
+}
+
+func TestParseCalendar(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Calendar
+		wantOK bool
+	}{
+		{"be", Buddhist, true},
+		{" พ.ศ. ", Buddhist, true},
+		{"AD", Gregorian, true},
+		{"ce", Gregorian, true},
+		{"julian", Gregorian, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseCalendar(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseCalendar(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package thai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ตัวอักษรที่ห้ามขึ้นต้นบรรทัด จะติดไปกับคำก่อนหน้า
+const noLineStart = "ๆฯ,.;:!?)]}%”’"
+
+// LineBreaks แบ่งข้อความเป็นช่วงที่ตัดบรรทัดระหว่างกันได้ (ต่อกันแล้วได้ข้อความเดิมทุกตัวอักษร)
+// ภาษาไทยตัดตามคำจากพจนานุกรม ส่วนอื่นตัดที่ช่องว่าง; ช่องว่างติดท้ายช่วงก่อนหน้า
+func LineBreaks(s string) []string {
+	rs := []rune(s)
+	out := []string{}
+	add := func(piece string, glue bool) {
+		if glue && len(out) > 0 {
+			out[len(out)-1] += piece
+			return
+		}
+		out = append(out, piece)
+	}
+
+	i := 0
+	for i < len(rs) {
+		r := rs[i]
+		j := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			for j < len(rs) && unicode.IsSpace(rs[j]) {
+				j++
+			}
+			add(string(rs[i:j]), true)
+		case isThaiLetter(r):
+			if k := thaiAbbrevEnd(rs, i); k > i {
+				j = k
+				add(string(rs[i:j]), false)
+				break
+			}
+			for j < len(rs) && isThaiLetter(rs[j]) {
+				j++
+			}
+			for _, w := range segmentRun(rs[i:j], "") {
+				add(w, false)
+			}
+		default:
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !isThaiLetter(rs[j]) {
+				j++
+			}
+			add(string(rs[i:j]), strings.ContainsRune(noLineStart, r))
+		}
+		i = j
+	}
+	return out
+}
@@ -0,0 +1,235 @@
+package thai
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed words.txt
+var wordsFile string
+
+var (
+	dictOnce     sync.Once
+	dict         map[string]bool
+	maxWordRunes int
+)
+
+func loadDict() {
+	dict = map[string]bool{}
+	sc := bufio.NewScanner(strings.NewReader(wordsFile))
+	for sc.Scan() {
+		w := strings.TrimSpace(sc.Text())
+		if w == "" || strings.HasPrefix(w, "#") {
+			continue
+		}
+		dict[w] = true
+		if n := len([]rune(w)); n > maxWordRunes {
+			maxWordRunes = n
+		}
+	}
+}
+
+func isLeadingVowel(r rune) bool { return r >= 'เ' && r <= 'ไ' } // เ แ โ ใ ไ
+
+// isFollowing: สระ/วรรณยุกต์ที่ต้องเกาะกับพยัญชนะตัวหน้า
+func isFollowing(r rune) bool {
+	return (r >= 0x0E30 && r <= 0x0E3A) || r == 0x0E45 || (r >= 0x0E47 && r <= 0x0E4E)
+}
+
+func isConsonant(r rune) bool { return r >= 'ก' && r <= 'ฮ' }
+
+// isThaiLetter: ตัวอักษรไทยที่เป็นส่วนหนึ่งของคำ (ไม่รวมเลขไทยและเครื่องหมาย ฯ ๆ ๏ ฯลฯ)
+func isThaiLetter(r rune) bool {
+	return (r >= 'ก' && r <= 'ฮ') || (r >= 0x0E30 && r <= 0x0E3A) || (r >= 0x0E40 && r <= 0x0E45) || (r >= 0x0E47 && r <= 0x0E4E)
+}
+
+// clusterBounds แบ่งข้อความไทยเป็นหน่วยที่ตัดแยกไม่ได้ (Thai character cluster แบบย่อ)
+// คืนตำแหน่งขอบเขตทั้งหมด รวม 0 และ len(rs)
+func clusterBounds(rs []rune) []int {
+	bounds := []int{0}
+	i := 0
+	for i < len(rs) {
+		j := i
+		if isLeadingVowel(rs[j]) {
+			j++
+		}
+		if j < len(rs) && !isFollowing(rs[j]) {
+			j++
+		}
+		for j < len(rs) && isFollowing(rs[j]) {
+			j++
+		}
+		// ตัวการันต์ (เช่น ศักดิ์, จันทร์) ติดไปกับพยางค์ก่อนหน้า
+		for j < len(rs) && isConsonant(rs[j]) {
+			if j+1 < len(rs) && rs[j+1] == '์' {
+				j += 2
+			} else if j+2 < len(rs) && (rs[j+1] == 'ิ' || rs[j+1] == 'ุ' || rs[j+1] == 'ร') && rs[j+2] == '์' {
+				j += 3
+			} else {
+				break
+			}
+		}
+		if j == i {
+			j++
+		}
+		i = j
+		bounds = append(bounds, i)
+	}
+	return bounds
+}
+
+type segCost struct {
+	unknown, words int
+	prev           int // index ใน bounds ของจุดเริ่มคำ
+	known          bool
+}
+
+func (a segCost) less(b segCost) bool {
+	if a.unknown != b.unknown {
+		return a.unknown < b.unknown
+	}
+	return a.words < b.words
+}
+
+// segmentRun ตัดคำข้อความไทยล้วนด้วย maximal matching (คำน้อยที่สุด โดยให้คำที่ไม่รู้จักน้อยที่สุดก่อน)
+// exclude คือคำที่ห้ามใช้ทั้งคำ (ใช้ตอนแตกคำประสม)
+func segmentRun(rs []rune, exclude string) []string {
+	dictOnce.Do(loadDict)
+
+	bounds := clusterBounds(rs)
+	n := len(bounds)
+	best := make([]segCost, n)
+	for i := 1; i < n; i++ {
+		best[i] = segCost{unknown: 1 << 30}
+	}
+
+	for i := 0; i < n-1; i++ {
+		if best[i].unknown == 1<<30 {
+			continue
+		}
+		// คำที่ไม่รู้จัก: ข้ามไปทีละ cluster
+		unk := segCost{unknown: best[i].unknown + 1, words: best[i].words + 1, prev: i}
+		if unk.less(best[i+1]) {
+			best[i+1] = unk
+		}
+		for j := i + 1; j < n && bounds[j]-bounds[i] <= maxWordRunes; j++ {
+			w := string(rs[bounds[i]:bounds[j]])
+			if !dict[w] || w == exclude {
+				continue
+			}
+			cand := segCost{unknown: best[i].unknown, words: best[i].words + 1, prev: i, known: true}
+			if cand.less(best[j]) {
+				best[j] = cand
+			}
+		}
+	}
+
+	// ย้อนกลับเพื่อสร้างคำ แล้วรวม cluster ที่ไม่รู้จักที่อยู่ติดกันเป็นคำเดียว
+	type piece struct {
+		from, to int
+		known    bool
+	}
+	pieces := []piece{}
+	for j := n - 1; j > 0; j = best[j].prev {
+		pieces = append(pieces, piece{from: bounds[best[j].prev], to: bounds[j], known: best[j].known})
+	}
+	out := []string{}
+	for k := len(pieces) - 1; k >= 0; k-- {
+		p := pieces[k]
+		if !p.known {
+			for k > 0 && !pieces[k-1].known {
+				k--
+				p.to = pieces[k].to
+			}
+		}
+		out = append(out, string(rs[p.from:p.to]))
+	}
+	return out
+}
+
+// Segment ตัดข้อความเป็นคำ: ส่วนที่เป็นภาษาไทยใช้พจนานุกรม ส่วนอื่นตัดที่ช่องว่าง/เครื่องหมาย
+// ตัวเลขที่คั่นด้วย / จะรวมเป็นคำเดียว (เช่น 1234/2565) และคำย่อไทย เช่น ป.พ.พ. จะคงจุดไว้
+func Segment(s string) []string {
+	rs := []rune(s)
+	out := []string{}
+	i := 0
+	for i < len(rs) {
+		r := rs[i]
+		switch {
+		case isThaiLetter(r):
+			if j := thaiAbbrevEnd(rs, i); j > i {
+				out = append(out, string(rs[i:j]))
+				i = j
+				continue
+			}
+			j := i
+			for j < len(rs) && isThaiLetter(rs[j]) {
+				j++
+			}
+			out = append(out, segmentRun(rs[i:j], "")...)
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i
+			for j < len(rs) {
+				if unicode.IsLetter(rs[j]) && !IsThai(rs[j]) || unicode.IsDigit(rs[j]) {
+					j++
+					continue
+				}
+				// 1234/2565
+				if rs[j] == '/' && j+1 < len(rs) && unicode.IsDigit(rs[j+1]) && j > i && unicode.IsDigit(rs[j-1]) {
+					j++
+					continue
+				}
+				break
+			}
+			out = append(out, string(rs[i:j]))
+			i = j
+		default:
+			i++
+		}
+	}
+	return out
+}
+
+// thaiAbbrevEnd หาคำย่อแบบ "ป.พ.พ." / "พ.ศ." (อักษรไทย 1-3 ตัวตามด้วยจุด อย่างน้อยสองชุด)
+// คืน i ถ้าไม่ใช่คำย่อ
+func thaiAbbrevEnd(rs []rune, i int) int {
+	j, parts := i, 0
+	for {
+		k := j
+		for k < len(rs) && k-j < 4 && isThaiLetter(rs[k]) {
+			k++
+		}
+		if k == j || k-j > 3 || k >= len(rs) || rs[k] != '.' {
+			break
+		}
+		j = k + 1
+		parts++
+	}
+	if parts < 2 {
+		return i
+	}
+	return j
+}
+
+// SubWords แตกคำประสมที่อยู่ในพจนานุกรมเป็นคำย่อย (เช่น ลักทรัพย์ → ลัก, ทรัพย์)
+// คืน nil ถ้าแตกไม่ได้ทั้งหมดเป็นคำที่รู้จัก
+func SubWords(word string) []string {
+	dictOnce.Do(loadDict)
+	if !dict[word] {
+		return nil
+	}
+	parts := segmentRun([]rune(word), word)
+	if len(parts) < 2 {
+		return nil
+	}
+	for _, p := range parts {
+		if !dict[p] {
+			return nil
+		}
+	}
+	return parts
+}
@@ -0,0 +1,36 @@
+package thai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"จำเลยลักทรัพย์ของโจทก์", []string{"จำเลย", "ลักทรัพย์", "ของ", "โจทก์"}},
+		{"ตาม ป.พ.พ. มาตรา 420", []string{"ตาม", "ป.พ.พ.", "มาตรา", "420"}},
+		{"ฎีกาที่ 1234/2565", []string{"ฎีกา", "ที่", "1234/2565"}},
+		{"ฎีกาที่ ๑๒๓๔/๒๕๖๕", []string{"ฎีกา", "ที่", "๑๒๓๔/๒๕๖๕"}},
+		{"Supreme Court, 2565", []string{"Supreme", "Court", "2565"}},
+		// คำที่ไม่รู้จักติดกันรวมเป็นคำเดียว
+		{"ลักทรัพย์ฟฤหฬฮฆ", []string{"ลักทรัพย์", "ฟฤหฬฮฆ"}},
+	}
+	for _, tt := range tests {
+		if got := Segment(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Segment(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSubWords(t *testing.T) {
+	if got := SubWords("ลักทรัพย์"); !reflect.DeepEqual(got, []string{"ลัก", "ทรัพย์"}) {
+		t.Errorf("SubWords(ลักทรัพย์) = %q", got)
+	}
+	if got := SubWords("ฟฤหฬฮฆ"); got != nil {
+		t.Errorf("SubWords(unknown) = %q, want nil", got)
+	}
+}
@@ -0,0 +1,37 @@
+// Package thai รวมเครื่องมือเกี่ยวกับภาษาไทยที่ระบบใช้ร่วมกัน เช่น ตัดคำ และแปลงเลขไทย
+package thai
+
+import "strings"
+
+// IsThai: ตัวอักษรอยู่ในช่วง Unicode ภาษาไทย (U+0E00–U+0E7F)
+func IsThai(r rune) bool {
+	return r >= 0x0E00 && r <= 0x0E7F
+}
+
+// IsThaiDigit: ๐-๙
+func IsThaiDigit(r rune) bool {
+	return r >= '๐' && r <= '๙'
+}
+
+// NormalizeDigits แปลงเลขไทย (๐-๙) เป็นเลขอารบิก
+func NormalizeDigits(s string) string {
+	if !strings.ContainsFunc(s, IsThaiDigit) {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if IsThaiDigit(r) {
+			return '0' + (r - '๐')
+		}
+		return r
+	}, s)
+}
+
+// ToThaiDigits แปลงเลขอารบิกเป็นเลขไทย
+func ToThaiDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return '๐' + (r - '0')
+		}
+		return r
+	}, s)
+}
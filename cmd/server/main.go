@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"judgment-notes/cmd/internal/db"
 	"judgment-notes/cmd/internal/httpapi"
+	"judgment-notes/cmd/internal/mail"
+	"judgment-notes/cmd/internal/storage"
 	"log"
 	"os"
 
@@ -34,7 +37,28 @@ func main() {
 	}
 	defer pool.Close()
 
-	r := httpapi.NewRouter(pool)
+	// ที่เก็บไฟล์แนบ (STORAGE_BACKEND=local|s3)
+	store, err := storage.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ส่งอีเมล reset รหัสผ่าน / ยืนยันอีเมล (MAIL_BACKEND=log|file|smtp)
+	mailer, err := mail.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ลบถาวรรายการในถังขยะที่เกินกำหนด (TRASH_RETENTION_DAYS)
+	httpapi.StartTrashPurger(context.Background(), pool, store)
+
+	// สร้าง search index ให้ข้อมูลที่ยังไม่มี / index เวอร์ชันเก่า
+	httpapi.StartReindexer(context.Background(), pool)
+
+	// ดึงข้อความจากไฟล์แนบเพื่อใช้ค้นหา
+	httpapi.StartAttachmentExtractor(context.Background(), pool, store)
+
+	r := httpapi.NewRouter(pool, store, mailer)
 	log.Printf("API listening on :%s", port)
 	if err := r.Run(":" + port); err != nil {
 		log.Fatal(err)
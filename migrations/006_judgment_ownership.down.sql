@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_judgments_created_by;
+ALTER TABLE judgments DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE judgments DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,6 @@
+-- เก็บว่าใครสร้าง/แก้ไข judgment ล่าสุด (ผูกกับ users.id)
+ALTER TABLE judgments
+  ADD COLUMN IF NOT EXISTS created_by uuid NULL REFERENCES users(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS updated_by uuid NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_created_by ON judgments (created_by);
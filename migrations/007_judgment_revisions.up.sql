@@ -0,0 +1,32 @@
+-- ประวัติการแก้ไข judgment ทุกครั้ง (snapshot ทั้งก้อนเป็น jsonb)
+-- ไม่ผูก FK กับ judgments เพื่อให้ประวัติยังอยู่แม้ judgment ถูกลบ
+CREATE TABLE IF NOT EXISTS judgment_revisions (
+  id bigserial PRIMARY KEY,
+  judgment_id uuid NOT NULL,
+  rev int NOT NULL,
+  action text NOT NULL,
+  snapshot jsonb NOT NULL,
+  changed_by uuid NULL REFERENCES users(id) ON DELETE SET NULL,
+  changed_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT judgment_revisions_rev_uniq UNIQUE (judgment_id, rev),
+  CONSTRAINT judgment_revisions_action_chk CHECK (action IN ('create','update','delete','restore'))
+);
+
+-- ข้อมูลที่มีอยู่แล้ว: เก็บสถานะปัจจุบันเป็น revision แรก
+INSERT INTO judgment_revisions (judgment_id, rev, action, snapshot, changed_by, changed_at)
+SELECT j.id, 1, 'create',
+       jsonb_build_object(
+         'title', j.title,
+         'case_no', j.case_no,
+         'court', j.court,
+         'judgment_date', to_char(j.judgment_date,'YYYY-MM-DD'),
+         'parties', j.parties,
+         'facts', j.facts,
+         'issues', j.issues,
+         'holding', j.holding,
+         'notes', j.notes,
+         'tags', to_jsonb(j.tags)
+       ),
+       j.created_by, j.updated_at
+FROM judgments j
+WHERE NOT EXISTS (SELECT 1 FROM judgment_revisions r WHERE r.judgment_id = j.id);
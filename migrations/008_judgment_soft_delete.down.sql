@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_judgments_deleted_at;
+ALTER TABLE judgments DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE judgments DROP COLUMN IF EXISTS deleted_at;
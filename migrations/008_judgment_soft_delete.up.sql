@@ -0,0 +1,6 @@
+-- ลบแบบ soft delete: ย้ายไปถังขยะก่อน แล้วค่อย purge ทีหลัง
+ALTER TABLE judgments
+  ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS deleted_by uuid NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_deleted_at ON judgments (deleted_at) WHERE deleted_at IS NOT NULL;
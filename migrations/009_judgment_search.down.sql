@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_judgments_index_version;
+DROP INDEX IF EXISTS idx_judgments_search;
+ALTER TABLE judgments DROP COLUMN IF EXISTS index_version;
+ALTER TABLE judgments DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,8 @@
+-- full-text search: search_vector สร้างจากฝั่ง Go (ตัดคำไทยเอง) แล้วเก็บเป็น tsvector
+-- index_version ใช้บอกว่าแถวไหนต้อง reindex เมื่อเปลี่ยนวิธีสร้าง index
+ALTER TABLE judgments
+  ADD COLUMN IF NOT EXISTS search_vector tsvector NULL,
+  ADD COLUMN IF NOT EXISTS index_version int NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_search ON judgments USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_judgments_index_version ON judgments (index_version);
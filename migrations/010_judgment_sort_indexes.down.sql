@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_judgments_list_title;
+DROP INDEX IF EXISTS idx_judgments_list_doc_no;
+DROP INDEX IF EXISTS idx_judgments_list_updated;
+DROP INDEX IF EXISTS idx_judgments_list_date;
@@ -0,0 +1,16 @@
+-- index ให้ตรงกับ ORDER BY ของ list (keyset pagination) เฉพาะรายการที่ยังไม่ถูกลบ
+CREATE INDEX IF NOT EXISTS idx_judgments_list_date
+  ON judgments ((COALESCE(judgment_date, '-infinity'::date)) DESC, updated_at DESC, id DESC)
+  WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_list_updated
+  ON judgments (updated_at DESC, id DESC)
+  WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_list_doc_no
+  ON judgments ((COALESCE(doc_no, '')), id)
+  WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_list_title
+  ON judgments (title, id)
+  WHERE deleted_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE judgments DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- เลข version ของ judgment (เพิ่มทุกครั้งที่แก้ไข) ใช้ทำ ETag / If-Match
+ALTER TABLE judgments
+  ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 1;
@@ -0,0 +1,14 @@
+-- ไฟล์แนบของ judgment (ตัวไฟล์อยู่ใน storage, ที่นี่เก็บแค่ metadata)
+CREATE TABLE IF NOT EXISTS judgment_attachments (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  judgment_id uuid NOT NULL REFERENCES judgments(id) ON DELETE CASCADE,
+  filename text NOT NULL,
+  content_type text NOT NULL,
+  size_bytes bigint NOT NULL,
+  sha256 text NOT NULL,
+  storage_key text NOT NULL UNIQUE,
+  uploaded_by uuid NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_judgment_attachments_judgment ON judgment_attachments (judgment_id, created_at);
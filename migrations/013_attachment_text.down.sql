@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_judgment_attachments_extract_queue;
+DROP INDEX IF EXISTS idx_judgment_attachments_search;
+ALTER TABLE judgment_attachments DROP CONSTRAINT IF EXISTS judgment_attachments_extract_status_chk;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS index_version;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS content_text;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS extracted_at;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS extract_started_at;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS extract_attempts;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS extract_error;
+ALTER TABLE judgment_attachments DROP COLUMN IF EXISTS extract_status;
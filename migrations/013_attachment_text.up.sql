@@ -0,0 +1,19 @@
+-- ข้อความที่ดึงจากไฟล์แนบ (ทำใน background worker) และ search_vector ของไฟล์แนบ
+-- extract_status: pending → processing → done / empty (ไม่มี text layer) / unsupported / failed
+ALTER TABLE judgment_attachments
+  ADD COLUMN IF NOT EXISTS extract_status text NOT NULL DEFAULT 'pending',
+  ADD COLUMN IF NOT EXISTS extract_error text NULL,
+  ADD COLUMN IF NOT EXISTS extract_attempts int NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS extract_started_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS extracted_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS content_text text NULL,
+  ADD COLUMN IF NOT EXISTS search_vector tsvector NULL,
+  ADD COLUMN IF NOT EXISTS index_version int NOT NULL DEFAULT 0;
+
+ALTER TABLE judgment_attachments
+  ADD CONSTRAINT judgment_attachments_extract_status_chk
+  CHECK (extract_status IN ('pending','processing','done','empty','unsupported','failed'));
+
+CREATE INDEX IF NOT EXISTS idx_judgment_attachments_search ON judgment_attachments USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS idx_judgment_attachments_extract_queue ON judgment_attachments (created_at)
+  WHERE extract_status IN ('pending','processing');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_judgments_case_ref;
+DROP FUNCTION IF EXISTS case_ref(text);
+DROP TABLE IF EXISTS judgment_citations;
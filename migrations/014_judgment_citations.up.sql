@@ -0,0 +1,28 @@
+-- การอ้างอิงระหว่าง judgment (citing อ้างถึง cited)
+-- source: manual = ผู้ใช้เพิ่มเอง, auto = ตรวจพบเลขคดีในเนื้อหา, rejected = ผู้ใช้ลบ auto ทิ้ง (ไม่ให้ตรวจพบซ้ำ)
+CREATE TABLE IF NOT EXISTS judgment_citations (
+  id bigserial PRIMARY KEY,
+  citing_id uuid NOT NULL REFERENCES judgments(id) ON DELETE CASCADE,
+  cited_id uuid NOT NULL REFERENCES judgments(id) ON DELETE CASCADE,
+  relation text NOT NULL DEFAULT 'cites',
+  pinpoint text NULL,
+  source text NOT NULL DEFAULT 'manual',
+  created_by uuid NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT judgment_citations_pair_uniq UNIQUE (citing_id, cited_id),
+  CONSTRAINT judgment_citations_not_self_chk CHECK (citing_id <> cited_id),
+  CONSTRAINT judgment_citations_relation_chk CHECK (relation IN ('cites','follows','distinguishes','overrules')),
+  CONSTRAINT judgment_citations_source_chk CHECK (source IN ('manual','auto','rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_judgment_citations_cited ON judgment_citations (cited_id);
+
+-- เลขคดีแบบ "เลข/ปี" จาก case_no (เลขไทยเป็นอารบิก ตัดคำนำหน้า ช่องว่าง และเลข 0 นำหน้า)
+-- เช่น 'ฎ.๐๑๒๓๔ / ๒๕๖๕' → '1234/2565'
+CREATE OR REPLACE FUNCTION case_ref(s text) RETURNS text AS $$
+  SELECT ltrim(regexp_replace(
+           substring(translate(s, '๐๑๒๓๔๕๖๗๘๙', '0123456789') from '\d+\s*/\s*\d{4}'),
+           '\s', '', 'g'), '0')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_case_ref ON judgments (case_ref(case_no));
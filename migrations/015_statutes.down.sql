@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS judgment_statutes;
+DROP TABLE IF EXISTS statute_sections;
+DROP TABLE IF EXISTS statutes;
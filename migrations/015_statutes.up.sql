@@ -0,0 +1,35 @@
+-- ประมวลกฎหมายที่ระบบรู้จัก (ต้องตรงกับ legal.Statutes ฝั่ง Go ที่ใช้ตรวจหามาตราในข้อความ)
+CREATE TABLE IF NOT EXISTS statutes (
+  code text PRIMARY KEY,
+  abbr text NOT NULL,
+  name text NOT NULL
+);
+
+INSERT INTO statutes (code, abbr, name) VALUES
+  ('civil', 'ป.พ.พ.', 'ประมวลกฎหมายแพ่งและพาณิชย์'),
+  ('criminal', 'ป.อ.', 'ประมวลกฎหมายอาญา'),
+  ('civil-procedure', 'ป.วิ.พ.', 'ประมวลกฎหมายวิธีพิจารณาความแพ่ง'),
+  ('criminal-procedure', 'ป.วิ.อ.', 'ประมวลกฎหมายวิธีพิจารณาความอาญา'),
+  ('revenue', 'ป.รัษฎากร', 'ประมวลรัษฎากร'),
+  ('land', 'ป.ที่ดิน', 'ประมวลกฎหมายที่ดิน'),
+  ('constitution', 'รัฐธรรมนูญ', 'รัฐธรรมนูญแห่งราชอาณาจักรไทย')
+ON CONFLICT (code) DO NOTHING;
+
+-- มาตรา (เลขแบบ '420' หรือ '188/1') สร้างอัตโนมัติเมื่อพบใน judgment, title ให้ admin ใส่ภายหลัง
+CREATE TABLE IF NOT EXISTS statute_sections (
+  id bigserial PRIMARY KEY,
+  statute_code text NOT NULL REFERENCES statutes(code) ON UPDATE CASCADE,
+  section text NOT NULL,
+  title text NULL,
+  CONSTRAINT statute_sections_uniq UNIQUE (statute_code, section),
+  CONSTRAINT statute_sections_section_chk CHECK (section ~ '^[1-9][0-9]*(/[1-9][0-9]*)?$')
+);
+
+-- judgment ที่อ้าง/วินิจฉัยมาตรา (ตรวจพบจากเนื้อหาตอนบันทึก)
+CREATE TABLE IF NOT EXISTS judgment_statutes (
+  judgment_id uuid NOT NULL REFERENCES judgments(id) ON DELETE CASCADE,
+  section_id bigint NOT NULL REFERENCES statute_sections(id) ON DELETE CASCADE,
+  PRIMARY KEY (judgment_id, section_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_judgment_statutes_section ON judgment_statutes (section_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_judgments_court_id;
+ALTER TABLE judgments DROP COLUMN IF EXISTS court_id;
+DROP FUNCTION IF EXISTS resolve_court(text);
+DROP TABLE IF EXISTS court_aliases;
+DROP TABLE IF EXISTS courts;
+DROP FUNCTION IF EXISTS court_key(text);
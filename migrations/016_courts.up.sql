@@ -0,0 +1,135 @@
+-- ทะเบียนศาล (แทน court ที่เป็นข้อความอิสระ)
+-- level: supreme = ศาลสูงสุดของแต่ละระบบ, appeal = ชั้นอุทธรณ์, first_instance = ศาลชั้นต้น
+CREATE TABLE IF NOT EXISTS courts (
+  id serial PRIMARY KEY,
+  name_th text NOT NULL,
+  name_en text NULL,
+  level text NOT NULL DEFAULT 'first_instance',
+  region text NULL,
+  parent_id int NULL REFERENCES courts(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT courts_level_chk CHECK (level IN ('supreme','appeal','first_instance')),
+  CONSTRAINT courts_not_own_parent_chk CHECK (parent_id <> id)
+);
+
+-- ตัวเทียบชื่อศาล: ไม่สนตัวพิมพ์ ช่องว่าง และจุด ('ศาล ฎีกา' = 'ศาลฎีกา', 'supreme court' = 'Supreme Court')
+CREATE OR REPLACE FUNCTION court_key(s text) RETURNS text AS $$
+  SELECT NULLIF(lower(regexp_replace(s, '[[:space:].]+', '', 'g')), '')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courts_name_th ON courts (court_key(name_th));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courts_name_en ON courts (court_key(name_en));
+
+-- ชื่อเรียกอื่นของศาล (ชื่อย่อ ชื่อเก่า ฯลฯ) ใช้ได้เพียงศาลเดียว
+CREATE TABLE IF NOT EXISTS court_aliases (
+  court_id int NOT NULL REFERENCES courts(id) ON DELETE CASCADE,
+  alias text NOT NULL,
+  alias_key text GENERATED ALWAYS AS (court_key(alias)) STORED,
+  PRIMARY KEY (court_id, alias),
+  CONSTRAINT court_aliases_key_uniq UNIQUE (alias_key)
+);
+
+-- หา court id จากชื่อไทย ชื่ออังกฤษ หรือ alias (ชื่อจริงก่อน alias) คืน NULL ถ้าไม่รู้จัก
+CREATE OR REPLACE FUNCTION resolve_court(s text) RETURNS int AS $$
+  SELECT id FROM (
+    SELECT c.id, 1 AS pri FROM courts c
+    WHERE court_key(c.name_th) = court_key(s) OR court_key(c.name_en) = court_key(s)
+    UNION ALL
+    SELECT a.court_id, 2 FROM court_aliases a WHERE a.alias_key = court_key(s)
+  ) m
+  ORDER BY pri
+  LIMIT 1
+$$ LANGUAGE sql STABLE;
+
+INSERT INTO courts (name_th, name_en, level, region) VALUES
+  ('ศาลฎีกา', 'Supreme Court', 'supreme', NULL),
+  ('ศาลอุทธรณ์', 'Court of Appeal', 'appeal', NULL),
+  ('ศาลอุทธรณ์คดีชำนัญพิเศษ', 'Court of Appeal for Specialized Cases', 'appeal', NULL),
+  ('ศาลอุทธรณ์ภาค 1', 'Court of Appeal Region 1', 'appeal', 'ภาค 1'),
+  ('ศาลอุทธรณ์ภาค 2', 'Court of Appeal Region 2', 'appeal', 'ภาค 2'),
+  ('ศาลอุทธรณ์ภาค 3', 'Court of Appeal Region 3', 'appeal', 'ภาค 3'),
+  ('ศาลอุทธรณ์ภาค 4', 'Court of Appeal Region 4', 'appeal', 'ภาค 4'),
+  ('ศาลอุทธรณ์ภาค 5', 'Court of Appeal Region 5', 'appeal', 'ภาค 5'),
+  ('ศาลอุทธรณ์ภาค 6', 'Court of Appeal Region 6', 'appeal', 'ภาค 6'),
+  ('ศาลอุทธรณ์ภาค 7', 'Court of Appeal Region 7', 'appeal', 'ภาค 7'),
+  ('ศาลอุทธรณ์ภาค 8', 'Court of Appeal Region 8', 'appeal', 'ภาค 8'),
+  ('ศาลอุทธรณ์ภาค 9', 'Court of Appeal Region 9', 'appeal', 'ภาค 9'),
+  ('ศาลแพ่ง', 'Civil Court', 'first_instance', 'กรุงเทพมหานคร'),
+  ('ศาลอาญา', 'Criminal Court', 'first_instance', 'กรุงเทพมหานคร'),
+  ('ศาลเยาวชนและครอบครัวกลาง', 'Central Juvenile and Family Court', 'first_instance', 'กรุงเทพมหานคร'),
+  ('ศาลแรงงานกลาง', 'Central Labour Court', 'first_instance', 'กรุงเทพมหานคร'),
+  ('ศาลภาษีอากรกลาง', 'Central Tax Court', 'first_instance', 'กรุงเทพมหานคร'),
+  ('ศาลทรัพย์สินทางปัญญาและการค้าระหว่างประเทศกลาง', 'Central Intellectual Property and International Trade Court', 'first_instance', 'กรุงเทพมหานคร'),
+  ('ศาลล้มละลายกลาง', 'Central Bankruptcy Court', 'first_instance', 'กรุงเทพมหานคร'),
+  ('ศาลรัฐธรรมนูญ', 'Constitutional Court', 'supreme', NULL),
+  ('ศาลปกครองสูงสุด', 'Supreme Administrative Court', 'supreme', NULL),
+  ('ศาลปกครองกลาง', 'Central Administrative Court', 'first_instance', 'กรุงเทพมหานคร')
+ON CONFLICT DO NOTHING;
+
+UPDATE courts c SET parent_id = p.id
+FROM (VALUES
+  ('ศาลอุทธรณ์', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์คดีชำนัญพิเศษ', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 1', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 2', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 3', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 4', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 5', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 6', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 7', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 8', 'ศาลฎีกา'),
+  ('ศาลอุทธรณ์ภาค 9', 'ศาลฎีกา'),
+  ('ศาลแพ่ง', 'ศาลอุทธรณ์'),
+  ('ศาลอาญา', 'ศาลอุทธรณ์'),
+  ('ศาลเยาวชนและครอบครัวกลาง', 'ศาลอุทธรณ์'),
+  ('ศาลแรงงานกลาง', 'ศาลอุทธรณ์คดีชำนัญพิเศษ'),
+  ('ศาลภาษีอากรกลาง', 'ศาลอุทธรณ์คดีชำนัญพิเศษ'),
+  ('ศาลทรัพย์สินทางปัญญาและการค้าระหว่างประเทศกลาง', 'ศาลอุทธรณ์คดีชำนัญพิเศษ'),
+  ('ศาลล้มละลายกลาง', 'ศาลอุทธรณ์คดีชำนัญพิเศษ'),
+  ('ศาลปกครองกลาง', 'ศาลปกครองสูงสุด')
+) AS m(child, parent)
+JOIN courts p ON p.name_th = m.parent
+WHERE c.name_th = m.child;
+
+INSERT INTO court_aliases (court_id, alias)
+SELECT c.id, a.alias
+FROM (VALUES
+  ('ศาลฎีกา', 'ฎีกา'),
+  ('ศาลฎีกา', 'ฎ'),
+  ('ศาลฎีกา', 'Dika Court'),
+  ('ศาลอุทธรณ์', 'อุทธรณ์'),
+  ('ศาลอุทธรณ์', 'Appeal Court'),
+  ('ศาลอุทธรณ์คดีชำนัญพิเศษ', 'อุทธรณ์คดีชำนัญพิเศษ'),
+  ('ศาลอุทธรณ์คดีชำนัญพิเศษ', 'ศาลอุทธรณ์ชำนัญพิเศษ'),
+  ('ศาลอุทธรณ์ภาค 1', 'อุทธรณ์ภาค 1'),
+  ('ศาลอุทธรณ์ภาค 2', 'อุทธรณ์ภาค 2'),
+  ('ศาลอุทธรณ์ภาค 3', 'อุทธรณ์ภาค 3'),
+  ('ศาลอุทธรณ์ภาค 4', 'อุทธรณ์ภาค 4'),
+  ('ศาลอุทธรณ์ภาค 5', 'อุทธรณ์ภาค 5'),
+  ('ศาลอุทธรณ์ภาค 6', 'อุทธรณ์ภาค 6'),
+  ('ศาลอุทธรณ์ภาค 7', 'อุทธรณ์ภาค 7'),
+  ('ศาลอุทธรณ์ภาค 8', 'อุทธรณ์ภาค 8'),
+  ('ศาลอุทธรณ์ภาค 9', 'อุทธรณ์ภาค 9'),
+  ('ศาลแรงงานกลาง', 'Labour Court'),
+  ('ศาลภาษีอากรกลาง', 'Tax Court'),
+  ('ศาลทรัพย์สินทางปัญญาและการค้าระหว่างประเทศกลาง', 'ศาลทรัพย์สินทางปัญญาฯ'),
+  ('ศาลทรัพย์สินทางปัญญาและการค้าระหว่างประเทศกลาง', 'IP&IT Court'),
+  ('ศาลล้มละลายกลาง', 'Bankruptcy Court')
+) AS a(court, alias)
+JOIN courts c ON c.name_th = a.court
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE judgments
+  ADD COLUMN IF NOT EXISTS court_id int NULL REFERENCES courts(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_court_id ON judgments (court_id);
+
+-- ผูกข้อมูลเดิมกับศาลตามชื่อ/alias และเปลี่ยน court เป็นชื่อมาตรฐาน (ค่าที่ไม่รู้จักคงไว้ตามเดิม)
+UPDATE judgments SET court_id = resolve_court(court)
+WHERE court IS NOT NULL AND court_id IS NULL;
+
+-- แถวที่เปลี่ยนชื่อศาล version +1 ให้ ETag เดิมใช้ไม่ได้
+UPDATE judgments j SET court = c.name_th, version = j.version + 1, index_version = 0
+FROM courts c
+WHERE c.id = j.court_id AND j.court IS DISTINCT FROM c.name_th;
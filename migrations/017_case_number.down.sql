@@ -0,0 +1,17 @@
+CREATE OR REPLACE FUNCTION case_ref(s text) RETURNS text AS $$
+  SELECT ltrim(regexp_replace(
+           substring(translate(s, '๐๑๒๓๔๕๖๗๘๙', '0123456789') from '\d+\s*/\s*\d{4}'),
+           '\s', '', 'g'), '0')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_case_ref ON judgments (case_ref(case_no));
+
+DROP INDEX IF EXISTS idx_judgments_case_no_error;
+DROP INDEX IF EXISTS idx_judgments_case_number;
+ALTER TABLE judgments
+  DROP CONSTRAINT IF EXISTS judgments_case_type_chk,
+  DROP COLUMN IF EXISTS case_no_error,
+  DROP COLUMN IF EXISTS case_year,
+  DROP COLUMN IF EXISTS case_number,
+  DROP COLUMN IF EXISTS case_prefix,
+  DROP COLUMN IF EXISTS case_type;
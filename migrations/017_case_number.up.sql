@@ -0,0 +1,18 @@
+-- ส่วนประกอบของเลขคดีที่แยกจาก case_no (Go: legal.ParseCaseNo) เก็บคู่กับข้อความเดิม
+-- คำนวณตอน reindex: case_no ที่แยกไม่ได้จะมี case_no_error และส่วนประกอบเป็น NULL
+ALTER TABLE judgments
+  ADD COLUMN IF NOT EXISTS case_type text NULL,
+  ADD COLUMN IF NOT EXISTS case_prefix text NULL,
+  ADD COLUMN IF NOT EXISTS case_number int NULL,
+  ADD COLUMN IF NOT EXISTS case_year int NULL,
+  ADD COLUMN IF NOT EXISTS case_no_error text NULL;
+
+ALTER TABLE judgments
+  ADD CONSTRAINT judgments_case_type_chk CHECK (case_type IN ('dika','black','red','unspecified'));
+
+CREATE INDEX IF NOT EXISTS idx_judgments_case_number ON judgments (case_year, case_number);
+CREATE INDEX IF NOT EXISTS idx_judgments_case_no_error ON judgments ((case_no_error IS NOT NULL)) WHERE case_no_error IS NOT NULL;
+
+-- citation จับคู่ด้วย case_number/case_year แทน case_ref() แล้ว
+DROP INDEX IF EXISTS idx_judgments_case_ref;
+DROP FUNCTION IF EXISTS case_ref(text);
@@ -0,0 +1,28 @@
+-- เลข doc_no ออกตามปี พ.ศ. ได้ (DOC_NO_CALENDAR=be → JG-2567-0001)
+-- ตัวนับแยกตามปีที่ใช้ในเลขอยู่แล้ว ปี ค.ศ. กับ พ.ศ. จึงไม่ชนกัน
+DROP FUNCTION IF EXISTS next_judgment_doc_no();
+
+CREATE OR REPLACE FUNCTION next_judgment_doc_no(be boolean DEFAULT false) RETURNS text AS $$
+DECLARE
+  y int := EXTRACT(YEAR FROM now()) + CASE WHEN be THEN 543 ELSE 0 END;
+  n int;
+BEGIN
+  LOOP
+    UPDATE judgment_doc_counters
+    SET last_no = last_no + 1
+    WHERE year = y
+    RETURNING last_no INTO n;
+
+    IF FOUND THEN
+      EXIT;
+    END IF;
+
+    BEGIN
+      INSERT INTO judgment_doc_counters(year, last_no) VALUES (y, 0);
+    EXCEPTION WHEN unique_violation THEN
+    END;
+  END LOOP;
+
+  RETURN 'JG-' || y::text || '-' || LPAD(n::text, 4, '0');
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,39 @@
+-- คืนตัวนับของ scheme default เป็นตัวนับรวมรายปีแบบเดิม (ตัวนับของ scheme อื่นถูกทิ้ง)
+DELETE FROM judgment_doc_counters
+WHERE scheme_id <> (SELECT id FROM doc_no_schemes WHERE code = 'default');
+ALTER TABLE judgment_doc_counters DROP CONSTRAINT IF EXISTS judgment_doc_counters_pkey;
+ALTER TABLE judgment_doc_counters DROP COLUMN IF EXISTS scheme_id;
+ALTER TABLE judgment_doc_counters ADD PRIMARY KEY (year);
+
+DROP TABLE IF EXISTS doc_no_schemes;
+
+DROP INDEX IF EXISTS idx_judgments_category;
+ALTER TABLE judgments DROP COLUMN IF EXISTS category;
+
+DROP INDEX IF EXISTS idx_courts_code;
+ALTER TABLE courts DROP COLUMN IF EXISTS code;
+
+CREATE OR REPLACE FUNCTION next_judgment_doc_no(be boolean DEFAULT false) RETURNS text AS $$
+DECLARE
+  y int := EXTRACT(YEAR FROM now()) + CASE WHEN be THEN 543 ELSE 0 END;
+  n int;
+BEGIN
+  LOOP
+    UPDATE judgment_doc_counters
+    SET last_no = last_no + 1
+    WHERE year = y
+    RETURNING last_no INTO n;
+
+    IF FOUND THEN
+      EXIT;
+    END IF;
+
+    BEGIN
+      INSERT INTO judgment_doc_counters(year, last_no) VALUES (y, 0);
+    EXCEPTION WHEN unique_violation THEN
+    END;
+  END LOOP;
+
+  RETURN 'JG-' || y::text || '-' || LPAD(n::text, 4, '0');
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,74 @@
+-- รหัสศาลสั้น ๆ ใช้ใน token {court} ของเลขเอกสาร
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS code text NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_courts_code ON courts (upper(code));
+
+UPDATE courts c SET code = m.code
+FROM (VALUES
+  ('ศาลฎีกา', 'SC'),
+  ('ศาลอุทธรณ์', 'CA'),
+  ('ศาลอุทธรณ์คดีชำนัญพิเศษ', 'CAS'),
+  ('ศาลอุทธรณ์ภาค 1', 'CA1'),
+  ('ศาลอุทธรณ์ภาค 2', 'CA2'),
+  ('ศาลอุทธรณ์ภาค 3', 'CA3'),
+  ('ศาลอุทธรณ์ภาค 4', 'CA4'),
+  ('ศาลอุทธรณ์ภาค 5', 'CA5'),
+  ('ศาลอุทธรณ์ภาค 6', 'CA6'),
+  ('ศาลอุทธรณ์ภาค 7', 'CA7'),
+  ('ศาลอุทธรณ์ภาค 8', 'CA8'),
+  ('ศาลอุทธรณ์ภาค 9', 'CA9'),
+  ('ศาลแพ่ง', 'CVC'),
+  ('ศาลอาญา', 'CRC'),
+  ('ศาลเยาวชนและครอบครัวกลาง', 'JFC'),
+  ('ศาลแรงงานกลาง', 'LBC'),
+  ('ศาลภาษีอากรกลาง', 'TXC'),
+  ('ศาลทรัพย์สินทางปัญญาและการค้าระหว่างประเทศกลาง', 'IPC'),
+  ('ศาลล้มละลายกลาง', 'BKC'),
+  ('ศาลรัฐธรรมนูญ', 'CC'),
+  ('ศาลปกครองสูงสุด', 'SAC'),
+  ('ศาลปกครองกลาง', 'CAC')
+) AS m(name, code)
+WHERE c.name_th = m.name AND c.code IS NULL;
+
+-- หมวดของ judgment (เช่น กลุ่มงาน) ใช้เลือกรูปแบบเลขเอกสารและ token {category}
+ALTER TABLE judgments ADD COLUMN IF NOT EXISTS category text NULL;
+CREATE INDEX IF NOT EXISTS idx_judgments_category ON judgments (category);
+
+-- รูปแบบเลขเอกสาร: judgment ใหม่ใช้ scheme ที่ court/category ตรงและเจาะจงที่สุด (default ใช้เมื่อไม่มีตัวไหนตรง)
+-- year_source: created = ปีที่สร้าง, judgment_date = ปีของวันที่พิพากษา (ไม่มีวันที่ใช้ปีที่สร้าง)
+-- calendar: ce / be, NULL = ตามค่า DOC_NO_CALENDAR ของ server
+CREATE TABLE IF NOT EXISTS doc_no_schemes (
+  id serial PRIMARY KEY,
+  code text NOT NULL UNIQUE,
+  name text NOT NULL,
+  template text NOT NULL,
+  padding int NOT NULL DEFAULT 4,
+  reset text NOT NULL DEFAULT 'yearly',
+  year_source text NOT NULL DEFAULT 'created',
+  calendar text NULL,
+  court_id int NULL REFERENCES courts(id) ON DELETE CASCADE,
+  category text NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT doc_no_schemes_padding_chk CHECK (padding BETWEEN 1 AND 10),
+  CONSTRAINT doc_no_schemes_reset_chk CHECK (reset IN ('yearly','never')),
+  CONSTRAINT doc_no_schemes_year_source_chk CHECK (year_source IN ('created','judgment_date')),
+  CONSTRAINT doc_no_schemes_calendar_chk CHECK (calendar IN ('ce','be'))
+);
+
+-- court + category เดียวกันมีได้ scheme เดียว
+CREATE UNIQUE INDEX IF NOT EXISTS idx_doc_no_schemes_scope
+  ON doc_no_schemes (COALESCE(court_id, 0), COALESCE(lower(category), ''));
+
+INSERT INTO doc_no_schemes (code, name, template, padding, reset, year_source)
+VALUES ('default', 'เลขเอกสารทั่วไป', 'JG-{year}-{seq}', 4, 'yearly', 'created')
+ON CONFLICT (code) DO NOTHING;
+
+-- ตัวนับแยกตาม scheme; year คือรอบของตัวนับ (ปี หรือ 0 ถ้าไม่เริ่มใหม่)
+ALTER TABLE judgment_doc_counters ADD COLUMN IF NOT EXISTS scheme_id int NULL REFERENCES doc_no_schemes(id) ON DELETE CASCADE;
+UPDATE judgment_doc_counters SET scheme_id = (SELECT id FROM doc_no_schemes WHERE code = 'default') WHERE scheme_id IS NULL;
+ALTER TABLE judgment_doc_counters ALTER COLUMN scheme_id SET NOT NULL;
+ALTER TABLE judgment_doc_counters DROP CONSTRAINT IF EXISTS judgment_doc_counters_pkey;
+ALTER TABLE judgment_doc_counters ADD PRIMARY KEY (scheme_id, year);
+
+-- เลขเอกสารสร้างฝั่ง Go แล้ว (ดู package docno)
+DROP FUNCTION IF EXISTS next_judgment_doc_no(boolean);
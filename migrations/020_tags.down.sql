@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_judgments_tags;
+DROP FUNCTION IF EXISTS tag_with_descendants(text);
+DROP FUNCTION IF EXISTS resolve_tag(text);
+DROP TABLE IF EXISTS tag_synonyms;
+DROP TABLE IF EXISTS tags;
+DROP FUNCTION IF EXISTS tag_key(text);
@@ -0,0 +1,77 @@
+-- ทะเบียน tag (judgments.tags ยังเก็บเป็น text[] ของชื่อมาตรฐาน name_th)
+CREATE TABLE IF NOT EXISTS tags (
+  id serial PRIMARY KEY,
+  name_th text NOT NULL,
+  name_en text NULL,
+  parent_id int NULL REFERENCES tags(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT tags_not_own_parent_chk CHECK (parent_id <> id)
+);
+
+-- ตัวเทียบชื่อ tag: ไม่สนตัวพิมพ์ ช่องว่าง และ # ('สัญญา ซื้อขาย' = 'สัญญาซื้อขาย', '#Tax' = 'tax')
+CREATE OR REPLACE FUNCTION tag_key(s text) RETURNS text AS $$
+  SELECT NULLIF(lower(regexp_replace(s, '[[:space:]#]+', '', 'g')), '')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_th ON tags (tag_key(name_th));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_en ON tags (tag_key(name_en));
+CREATE INDEX IF NOT EXISTS idx_tags_parent_id ON tags (parent_id);
+
+-- คำพ้อง/คำสะกดผิดที่ให้แปลงเป็น tag มาตรฐาน (ใช้ได้เพียง tag เดียว)
+CREATE TABLE IF NOT EXISTS tag_synonyms (
+  tag_id int NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  synonym text NOT NULL,
+  synonym_key text GENERATED ALWAYS AS (tag_key(synonym)) STORED,
+  PRIMARY KEY (tag_id, synonym),
+  CONSTRAINT tag_synonyms_key_uniq UNIQUE (synonym_key)
+);
+
+-- หา tag id จากชื่อไทย ชื่ออังกฤษ หรือคำพ้อง (ชื่อจริงก่อน) คืน NULL ถ้าไม่รู้จัก
+CREATE OR REPLACE FUNCTION resolve_tag(s text) RETURNS int AS $$
+  SELECT id FROM (
+    SELECT t.id, 1 AS pri FROM tags t
+    WHERE tag_key(t.name_th) = tag_key(s) OR tag_key(t.name_en) = tag_key(s)
+    UNION ALL
+    SELECT ts.tag_id, 2 FROM tag_synonyms ts WHERE ts.synonym_key = tag_key(s)
+  ) m
+  ORDER BY pri
+  LIMIT 1
+$$ LANGUAGE sql STABLE;
+
+-- ชื่อ tag พร้อม tag ลูกหลานทั้งหมด (ใช้กับ filter ?tags=) ถ้าไม่รู้จักคืนค่าเดิม
+CREATE OR REPLACE FUNCTION tag_with_descendants(s text) RETURNS text[] AS $$
+  WITH RECURSIVE t AS (
+    SELECT id, name_th FROM tags WHERE id = resolve_tag(s)
+    UNION
+    SELECT c.id, c.name_th FROM tags c JOIN t ON c.parent_id = t.id
+  )
+  SELECT COALESCE(NULLIF(ARRAY(SELECT name_th FROM t), '{}'), ARRAY[s])
+$$ LANGUAGE sql STABLE;
+
+CREATE INDEX IF NOT EXISTS idx_judgments_tags ON judgments USING gin (tags);
+
+-- tag เดิมทุกตัวเข้าทะเบียน: ตัวที่ต่างกันแค่ตัวพิมพ์/ช่องว่างรวมเป็นตัวเดียว (ใช้การสะกดที่พบบ่อยที่สุด)
+INSERT INTO tags (name_th)
+SELECT DISTINCT ON (tag_key(x)) btrim(x)
+FROM judgments, unnest(tags) AS x
+WHERE tag_key(x) IS NOT NULL
+GROUP BY x
+ORDER BY tag_key(x), COUNT(*) DESC, x
+ON CONFLICT DO NOTHING;
+
+-- เขียน tags ของ judgment เป็นชื่อมาตรฐาน (ตัดตัวซ้ำ คงลำดับเดิม)
+WITH norm AS (
+  SELECT j.id, ARRAY(
+    SELECT t.name_th
+    FROM unnest(j.tags) WITH ORDINALITY AS u(x, n)
+    JOIN tags t ON t.id = resolve_tag(u.x)
+    GROUP BY t.name_th
+    ORDER BY min(u.n)
+  ) AS tags
+  FROM judgments j
+  WHERE cardinality(j.tags) > 0
+)
+UPDATE judgments j SET tags = norm.tags, version = j.version + 1, index_version = 0
+FROM norm
+WHERE j.id = norm.id AND j.tags IS DISTINCT FROM norm.tags;
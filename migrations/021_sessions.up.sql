@@ -0,0 +1,15 @@
+-- session ฝั่ง server: access token (JWT อายุสั้น) อ้าง session ผ่าน claim sid
+-- refresh token เก็บเฉพาะ sha256 และเปลี่ยนใหม่ทุกครั้งที่ refresh (previous_hash ไว้จับการใช้ token เก่าซ้ำ)
+CREATE TABLE IF NOT EXISTS sessions (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  refresh_hash text NOT NULL UNIQUE,
+  previous_hash text NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  last_used_at timestamptz NOT NULL DEFAULT now(),
+  expires_at timestamptz NOT NULL,
+  revoked_at timestamptz NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_hash ON sessions (previous_hash);
@@ -0,0 +1,8 @@
+-- ข้อมูลอุปกรณ์ของ session ให้ผู้ใช้ดูว่า login ค้างไว้ที่ไหนบ้าง
+ALTER TABLE sessions
+  ADD COLUMN IF NOT EXISTS ip text NULL,
+  ADD COLUMN IF NOT EXISTS user_agent text NULL,
+  ADD COLUMN IF NOT EXISTS device_label text NULL,
+  ADD COLUMN IF NOT EXISTS last_seen_at timestamptz NOT NULL DEFAULT now();
+
+UPDATE sessions SET last_seen_at = last_used_at;
@@ -0,0 +1,20 @@
+-- TOTP 2FA: secret เก็บแบบเข้ารหัส (AES-GCM) ฝั่ง server, enabled หลังยืนยันรหัสแรกแล้ว
+-- totp_last_step กันการใช้รหัสเดิมซ้ำภายในช่วงเวลาเดียวกัน
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS totp_secret text NULL,
+  ADD COLUMN IF NOT EXISTS totp_enabled_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS totp_last_step bigint NULL;
+
+-- บังคับ 2FA ราย role (user ที่ยังไม่เปิดใช้ทำได้เฉพาะตั้งค่า 2FA)
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS require_mfa boolean NOT NULL DEFAULT false;
+
+-- recovery code ใช้ได้ครั้งเดียว เก็บเฉพาะ bcrypt hash
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  id bigserial PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash text NOT NULL,
+  used_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user_id ON user_recovery_codes (user_id) WHERE used_at IS NULL;
@@ -0,0 +1,17 @@
+-- ยืนยันอีเมล: user เดิมถือว่ายืนยันแล้ว (ไม่โดนล็อกเมื่อเปิด REQUIRE_EMAIL_VERIFICATION)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamptz NULL;
+UPDATE users SET email_verified_at = COALESCE(created_at, now()) WHERE email_verified_at IS NULL;
+
+-- token ที่ส่งทางอีเมล (reset รหัสผ่าน / ยืนยันอีเมล) ใช้ได้ครั้งเดียว เก็บเฉพาะ sha256
+CREATE TABLE IF NOT EXISTS user_tokens (
+  id bigserial PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  purpose text NOT NULL,
+  token_hash text NOT NULL UNIQUE,
+  expires_at timestamptz NOT NULL,
+  used_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT user_tokens_purpose_chk CHECK (purpose IN ('password_reset','email_verify'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_purpose ON user_tokens (user_id, purpose) WHERE used_at IS NULL;
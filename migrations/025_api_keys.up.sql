@@ -0,0 +1,17 @@
+-- API key ของ user สำหรับ script/ระบบอื่น ใช้แทน login (ส่งเป็น Authorization: Bearer jnk_...)
+-- เก็บเฉพาะ sha256 ของ key, token_prefix ไว้ให้ผู้ใช้จำได้ว่าเป็น key ไหน
+CREATE TABLE IF NOT EXISTS api_keys (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name text NOT NULL,
+  token_prefix text NOT NULL,
+  token_hash text NOT NULL UNIQUE,
+  scopes text[] NOT NULL,
+  expires_at timestamptz NULL,
+  last_used_at timestamptz NULL,
+  last_used_ip text NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  revoked_at timestamptz NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,12 @@
+-- challenge ของขั้น 2FA ตอน login: นับรหัสผิดต่อ challenge (ผิดครบจำนวนแล้วใช้ต่อไม่ได้)
+-- และใช้รวมรหัสผิดของ user ในช่วงเวลาล่าสุดเพื่อล็อกชั่วคราว
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  failed_attempts int NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  expires_at timestamptz NOT NULL,
+  used_at timestamptz NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_challenges_user_created ON mfa_challenges (user_id, created_at);
@@ -0,0 +1,2 @@
+-- แปลงข้อมูลกลับไม่ได้ (ไม่รู้ว่าแถวไหนเคยเป็นปี พ.ศ.)
+SELECT 1;
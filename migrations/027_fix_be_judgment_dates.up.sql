@@ -0,0 +1,6 @@
+-- ก่อนหน้านี้ปี พ.ศ. อาจถูกเก็บเป็นปี ค.ศ. ตรง ๆ (เช่น 2567-03-15) แปลงกลับเป็น ค.ศ.
+-- version +1 ให้ ETag เดิมใช้ไม่ได้
+UPDATE judgments
+SET judgment_date = (judgment_date - interval '543 years')::date,
+    version = version + 1
+WHERE judgment_date >= date '2400-01-01';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_judgments_cited_refs;
+ALTER TABLE judgments DROP COLUMN IF EXISTS cited_refs;
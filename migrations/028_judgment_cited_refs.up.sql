@@ -0,0 +1,6 @@
+-- เลขคดีที่ judgment อ้างถึงในเนื้อหา (Go: legal.FindCaseRefs, รูป "เลข/ปี") รวมคดีที่ยังไม่มีในระบบ
+-- ใช้หา judgment ที่อ้างคดีที่เพิ่งเพิ่มเข้ามา; คำนวณตอน reindex
+ALTER TABLE judgments
+  ADD COLUMN IF NOT EXISTS cited_refs text[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_judgments_cited_refs ON judgments USING gin (cited_refs);